@@ -0,0 +1,62 @@
+package lru
+
+// Register an alias for a primary key, so that the item can be addressed by both. Aliases are
+// removed together with their item. Returns false if the primary key doesn't exist, or if the
+// alias is already a primary key.
+func (c *lru[K, V]) Alias(alias K, primary K) (ok bool) {
+	idx, ok := c.index(primary)
+
+	if !ok || !c.alive(idx) {
+		return false
+	}
+
+	// Aliases of aliases point directly at the primary key
+	primary = c.keys[idx]
+	alias = c.canonical(alias)
+
+	if alias == primary {
+		return false
+	}
+
+	if _, isPrimary := c.index(alias); isPrimary && c.resolve(alias) == alias {
+		return false
+	}
+
+	if c.aliases == nil {
+		c.aliases = make(map[K]K)
+	}
+
+	c.aliases[alias] = primary
+	return true
+}
+
+// Remove an alias, but keep its item.
+func (c *lru[K, V]) Unalias(alias K) (existed bool) {
+	alias = c.canonical(alias)
+
+	if _, existed = c.aliases[alias]; existed {
+		delete(c.aliases, alias)
+	}
+
+	return
+}
+
+func (c *lru[K, V]) resolve(key K) K {
+	key = c.canonical(key)
+
+	if len(c.aliases) > 0 {
+		if primary, ok := c.aliases[key]; ok {
+			return primary
+		}
+	}
+
+	return key
+}
+
+func (c *lru[K, V]) unaliasAll(primary K) {
+	for alias, p := range c.aliases {
+		if p == primary {
+			delete(c.aliases, alias)
+		}
+	}
+}
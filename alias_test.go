@@ -0,0 +1,56 @@
+package lru
+
+import "testing"
+
+func TestAlias(t *testing.T) {
+	var evicted []string
+
+	cache := New(2, func(key string, _ int) {
+		evicted = append(evicted, key)
+	})
+
+	cache.Set("id:1", 1)
+
+	if !cache.Alias("slug:one", "id:1") {
+		t.Fatal("expected alias to be registered")
+	}
+
+	if cache.Alias("slug:two", "id:2") {
+		t.Fatal("expected alias of a missing key to fail")
+	}
+
+	if v, ok := cache.Get("slug:one"); !ok || v != 1 {
+		t.Fatalf("expected 1 through alias, got %d", v)
+	}
+
+	cache.Replace("slug:one", 10)
+
+	if v, _ := cache.Get("id:1"); v != 10 || cache.Len() != 1 {
+		t.Fatalf("expected replace through alias to update the primary item, got %d", v)
+	}
+
+	cache.Set("id:2", 2)
+	cache.Set("id:3", 3)
+
+	if cache.Has("slug:one") {
+		t.Fatal("expected alias to be removed together with its evicted item")
+	}
+
+	if len(evicted) != 2 || evicted[1] != "id:1" {
+		t.Fatalf("expected id:1 to be evicted under its primary key, got %v", evicted)
+	}
+}
+
+func TestUnalias(t *testing.T) {
+	cache := New[string, int](2)
+	cache.Set("id:1", 1)
+	cache.Alias("slug:one", "id:1")
+
+	if !cache.Unalias("slug:one") || cache.Unalias("slug:one") {
+		t.Fatal("expected alias to be removed exactly once")
+	}
+
+	if cache.Has("slug:one") || !cache.Has("id:1") {
+		t.Fatal("expected only the alias to be removed")
+	}
+}
@@ -0,0 +1,187 @@
+package lru
+
+import (
+	"slices"
+	"sync"
+)
+
+// Backend is the persistent store behind a Backed cache, e.g. a database or a file system.
+type Backend[K comparable, V any] interface {
+	Load(key K) (val V, ok bool, err error)
+	Store(key K, val V) error
+	Delete(key K) error
+}
+
+// Backed is a thread-safe cache in front of a Backend. Misses are loaded from the backend, and
+// sets are persisted to it, either immediately or when evicted or flushed.
+type Backed[K comparable, V any] struct {
+	mu        sync.Mutex
+	cache     LRU[K, V]
+	backend   Backend[K, V]
+	writeBack bool
+	dirty     map[K]struct{}
+	err       error // First failed store of an evicted item, returned by Flush
+}
+
+// Create a cache that persists each set to the backend before it returns.
+func NewWriteThrough[K comparable, V any](capacity int, backend Backend[K, V], opts ...Option[K, V]) *Backed[K, V] {
+	return newBacked(capacity, backend, false, opts)
+}
+
+// Create a cache that persists sets to the backend when the item is evicted, or on Flush.
+// Unflushed sets are lost if the cache is discarded. Sets that the cache rejects, e.g. as all items
+// are pinned, are persisted immediately. A callback of WithEvicted is notified after the item is
+// persisted.
+func NewWriteBack[K comparable, V any](capacity int, backend Backend[K, V], opts ...Option[K, V]) *Backed[K, V] {
+	return newBacked(capacity, backend, true, opts)
+}
+
+func newBacked[K comparable, V any](capacity int, backend Backend[K, V], writeBack bool, opts []Option[K, V]) *Backed[K, V] {
+	b := &Backed[K, V]{
+		backend:   backend,
+		writeBack: writeBack,
+	}
+
+	if writeBack {
+		b.dirty = make(map[K]struct{})
+		opts = append(slices.Clip(opts), withEvictHook(b.evicted))
+	}
+
+	b.cache = NewWithOptions(capacity, opts...)
+
+	return b
+}
+
+// Number of cached items.
+func (b *Backed[K, V]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.cache.Len()
+}
+
+// Number of sets not yet persisted to the backend. Always zero for write-through caches.
+func (b *Backed[K, V]) Dirty() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.dirty)
+}
+
+// Get a cached item, or load it from the backend on a miss.
+func (b *Backed[K, V]) Get(key K) (val V, ok bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if val, ok = b.cache.Get(key); ok {
+		return
+	}
+
+	// An expired dirty item is newer than the backend's value, so it's stored before loading
+	if _, dirty := b.dirty[key]; dirty {
+		if err = b.flush(key); err != nil {
+			return
+		}
+	}
+
+	if val, ok, err = b.backend.Load(key); ok && err == nil {
+		b.cache.Upsert(key, val)
+	}
+
+	return
+}
+
+// Add or overwrite an item. A write-through cache only caches the item if the backend accepts it.
+func (b *Backed[K, V]) Set(key K, val V) (err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.writeBack {
+		if err = b.backend.Store(key, val); err != nil {
+			return
+		}
+	}
+
+	if b.cache.Upsert(key, val); !b.writeBack {
+		return
+	}
+
+	// A rejected item is stored right away, as it would otherwise be lost
+	if !b.cache.Has(key) {
+		delete(b.dirty, key)
+		return b.backend.Store(key, val)
+	}
+
+	b.dirty[key] = struct{}{}
+	return
+}
+
+// Remove an item from both the cache and the backend.
+func (b *Backed[K, V]) Remove(key K) (err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Not dirty anymore, so that the removed item isn't stored once evicted
+	delete(b.dirty, key)
+	b.cache.Remove(key)
+
+	return b.backend.Delete(key)
+}
+
+// Persist all dirty items to the backend. Returns the first error, including any failed store of
+// an evicted item since the last flush. Items that fail to persist remain dirty.
+func (b *Backed[K, V]) Flush() (err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	err, b.err = b.err, nil
+
+	for key := range b.dirty {
+		if e := b.flush(key); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return
+}
+
+// Persist a dirty item, which stays dirty if it fails.
+func (b *Backed[K, V]) flush(key K) error {
+	// An expired item is yet to be stored, unless removed, which stores it once evicted
+	val, ok := b.cache.(interface{ staleValue(K) (V, bool) }).staleValue(key)
+
+	if ok {
+		if err := b.backend.Store(key, val); err != nil {
+			return err
+		}
+	}
+
+	delete(b.dirty, key)
+	return nil
+}
+
+// Flush all dirty items to the backend, and close the cache.
+func (b *Backed[K, V]) Close() (err error) {
+	err = b.Flush()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if e := b.cache.Close(); err == nil {
+		err = e
+	}
+
+	return
+}
+
+func (b *Backed[K, V]) evicted(key K, val V) {
+	if _, ok := b.dirty[key]; !ok {
+		return
+	}
+
+	delete(b.dirty, key)
+
+	if err := b.backend.Store(key, val); err != nil && b.err == nil {
+		b.err = err
+	}
+}
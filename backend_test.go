@@ -0,0 +1,157 @@
+package lru
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type mapBackend struct {
+	items map[int]int
+	loads int
+	fail  bool
+}
+
+func (m *mapBackend) Load(key int) (val int, ok bool, err error) {
+	m.loads++
+	val, ok = m.items[key]
+	return
+}
+
+func (m *mapBackend) Store(key int, val int) error {
+	if m.fail {
+		return errors.New("store failed")
+	}
+
+	m.items[key] = val
+	return nil
+}
+
+func (m *mapBackend) Delete(key int) error {
+	delete(m.items, key)
+	return nil
+}
+
+func TestWriteThrough(t *testing.T) {
+	backend := &mapBackend{items: map[int]int{1: 1}}
+	cache := NewWriteThrough(2, backend)
+
+	if v, ok, err := cache.Get(1); !ok || err != nil || v != 1 {
+		t.Fatalf("expected a miss to be loaded from the backend, got %d, %v, %v", v, ok, err)
+	}
+
+	cache.Get(1)
+
+	if backend.loads != 1 {
+		t.Fatalf("expected a loaded item to be cached, got %d loads", backend.loads)
+	}
+
+	if err := cache.Set(2, 2); err != nil || backend.items[2] != 2 {
+		t.Fatal("expected a set to be persisted immediately")
+	}
+
+	backend.fail = true
+
+	if err := cache.Set(3, 3); err == nil || cache.Len() != 2 {
+		t.Fatal("expected a failed store to not be cached")
+	}
+}
+
+func TestWriteBack(t *testing.T) {
+	backend := &mapBackend{items: map[int]int{}}
+	cache := NewWriteBack(2, backend)
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+
+	if len(backend.items) != 0 || cache.Dirty() != 2 {
+		t.Fatal("expected sets to not be persisted before eviction or flush")
+	}
+
+	cache.Set(3, 3)
+
+	if backend.items[1] != 1 || cache.Dirty() != 2 {
+		t.Fatal("expected an evicted dirty item to be persisted")
+	}
+
+	if err := cache.Remove(2); err != nil || cache.Dirty() != 1 {
+		t.Fatal("expected a removed item to not be dirty")
+	}
+
+	if err := cache.Flush(); err != nil || backend.items[3] != 3 || cache.Dirty() != 0 {
+		t.Fatalf("expected flush to persist all dirty items, got %v", backend.items)
+	}
+
+	if _, ok := backend.items[2]; ok {
+		t.Fatal("expected a removed item to be deleted from the backend")
+	}
+}
+
+func TestWriteBackClose(t *testing.T) {
+	backend := &mapBackend{items: map[int]int{}}
+	cache := NewWriteBack(2, backend)
+
+	cache.Set(1, 1)
+
+	if err := cache.Close(); err != nil || backend.items[1] != 1 {
+		t.Fatal("expected dirty items to be flushed on close")
+	}
+}
+
+func TestWriteBackExpired(t *testing.T) {
+	backend := &mapBackend{items: map[int]int{}}
+	clock := newFakeClock()
+	cache := NewWriteBack(2, backend, WithClock[int, int](clock), WithExpireAfterWrite[int, int](time.Minute))
+
+	cache.Set(1, 1)
+	clock.Advance(time.Hour)
+
+	if err := cache.Flush(); err != nil || backend.items[1] != 1 || cache.Dirty() != 0 {
+		t.Fatalf("expected an expired dirty item to be persisted, got %v", backend.items)
+	}
+}
+
+func TestWriteBackGetExpired(t *testing.T) {
+	backend := &mapBackend{items: map[int]int{1: 100}}
+	clock := newFakeClock()
+	cache := NewWriteBack(2, backend, WithClock[int, int](clock), WithExpireAfterWrite[int, int](time.Minute))
+
+	cache.Set(1, 200)
+	clock.Advance(time.Hour)
+
+	if v, ok, err := cache.Get(1); err != nil || !ok || v != 200 || backend.items[1] != 200 {
+		t.Fatalf("expected the expired dirty value to be persisted before loading, got %d", v)
+	}
+}
+
+func TestWriteBackEvicted(t *testing.T) {
+	var evicted []int
+
+	backend := &mapBackend{items: map[int]int{}}
+	opts := make([]Option[int, int], 1, 2)
+	opts[0] = WithEvicted(func(key, _ int) {
+		evicted = append(evicted, key)
+	})
+
+	cache := NewWriteBack(1, backend, opts...)
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+
+	if len(evicted) != 1 || backend.items[1] != 1 {
+		t.Fatalf("expected both the evict callback and the backend to get the evicted item, got %v", evicted)
+	}
+
+	if opts[:2][1] != nil {
+		t.Fatal("expected the options to not be modified")
+	}
+}
+
+func TestWriteBackRejected(t *testing.T) {
+	backend := &mapBackend{items: map[int]int{}}
+	cache := NewWriteBack(0, backend)
+
+	if err := cache.Set(1, 1); err != nil || backend.items[1] != 1 || cache.Dirty() != 0 {
+		t.Fatal("expected a rejected set to be persisted instead of marked dirty")
+	}
+}
@@ -0,0 +1,26 @@
+package lru
+
+// Tx is the view of a cache within Batch.
+type Tx[K comparable, V any] interface {
+	Reserve(n int) (ok bool)
+	Has(key K) (ok bool)
+	Get(key K, opts ...CallOption) (val V, ok bool)
+	Set(key K, val V) (ok bool)
+	Replace(key K, val V) (existed bool)
+	Upsert(key K, val V) (updated bool)
+	Remove(key K) (existed bool)
+}
+
+// Run fn with a view of the cache, whose changes are applied as one with respect to other
+// goroutines. fn must only use the view, not the cache.
+func (c *lru[K, V]) Batch(fn func(tx Tx[K, V])) {
+	fn(c)
+}
+
+// Batch implements LRU.
+func (t *threadsafe[K, V]) Batch(fn func(tx Tx[K, V])) {
+	t.lock()
+	defer t.unlock()
+
+	fn(&t.lru)
+}
@@ -0,0 +1,52 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	cache := NewThreadSafe[string, int](8)
+	cache.Set("a", 100)
+	cache.Set("b", 0)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for range 10 {
+				cache.Batch(func(tx Tx[string, int]) {
+					a, _ := tx.Get("a")
+					b, _ := tx.Get("b")
+					tx.Replace("a", a-1)
+					tx.Replace("b", b+1)
+				})
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for range 10 {
+				cache.Batch(func(tx Tx[string, int]) {
+					a, _ := tx.Get("a")
+					b, _ := tx.Get("b")
+
+					if a+b != 100 {
+						t.Errorf("expected a consistent view, got %d and %d", a, b)
+					}
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if a, _ := cache.Get("a"); a != 20 {
+		t.Fatalf("expected no lost updates, got %d", a)
+	}
+}
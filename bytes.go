@@ -0,0 +1,206 @@
+package lru
+
+import (
+	"slices"
+)
+
+// Bytes is an LRU cache of byte slices, bounded by their total size rather than by count. Values
+// are copied into a single arena and referenced by offset and length, so that the heap holds no
+// pointer per value for the garbage collector to scan. Not thread-safe.
+type Bytes[K comparable] struct {
+	keys    []K
+	offs    []int
+	lens    []int
+	lastUse []uint64
+	tick    uint64
+	arena   []byte
+	used    int // Bytes of live values, which is less than len(arena) if there are holes
+}
+
+// Create a byte slice cache that holds at most maxBytes bytes of values.
+func NewBytes[K comparable](maxBytes int) *Bytes[K] {
+	return &Bytes[K]{
+		arena: make([]byte, 0, maxBytes),
+	}
+}
+
+// Number of items.
+func (b *Bytes[K]) Len() int {
+	return len(b.keys)
+}
+
+// Total size of all values, in bytes.
+func (b *Bytes[K]) Size() int {
+	return b.used
+}
+
+// Maximum total size of all values, in bytes.
+func (b *Bytes[K]) MaxBytes() int {
+	return cap(b.arena)
+}
+
+func (b *Bytes[K]) Has(key K) (ok bool) {
+	_, ok = b.index(key)
+	return
+}
+
+// Get a copy of a value.
+func (b *Bytes[K]) Get(key K) (val []byte, ok bool) {
+	return b.GetAppend(nil, key)
+}
+
+// Append a value to dst, and return the extended slice. Avoids an allocation when dst has room.
+func (b *Bytes[K]) GetAppend(dst []byte, key K) (val []byte, ok bool) {
+	idx, ok := b.index(key)
+
+	if !ok {
+		return dst, false
+	}
+
+	b.lastUse[idx] = b.nextTick()
+	return append(dst, b.value(idx)...), true
+}
+
+// Add a copy of a value only if the key doesn't exist. Values larger than MaxBytes are
+// rejected.
+func (b *Bytes[K]) Set(key K, val []byte) (ok bool) {
+	if b.Has(key) {
+		return
+	}
+
+	return b.append(key, val)
+}
+
+// Add or overwrite a copy of a value. Values larger than MaxBytes are rejected, and any existing
+// item with the key is removed.
+func (b *Bytes[K]) Replace(key K, val []byte) (existed bool) {
+	existed = b.Remove(key)
+	b.append(key, val)
+
+	return
+}
+
+func (b *Bytes[K]) Remove(key K) (existed bool) {
+	idx, existed := b.index(key)
+
+	if existed {
+		b.remove(idx)
+	}
+
+	return
+}
+
+// Clear cache.
+func (b *Bytes[K]) Reset() {
+	clear(b.keys)
+
+	b.keys = b.keys[:0]
+	b.offs = b.offs[:0]
+	b.lens = b.lens[:0]
+	b.lastUse = b.lastUse[:0]
+	b.arena = b.arena[:0]
+	b.used = 0
+	b.tick = 0
+}
+
+func (b *Bytes[K]) index(key K) (idx int, ok bool) {
+	for i := range b.keys {
+		if b.keys[i] == key {
+			return i, true
+		}
+	}
+
+	return
+}
+
+func (b *Bytes[K]) value(idx int) []byte {
+	off := b.offs[idx]
+	return b.arena[off : off+b.lens[idx] : off+b.lens[idx]]
+}
+
+func (b *Bytes[K]) append(key K, val []byte) (ok bool) {
+	n := len(val)
+
+	if n > cap(b.arena) {
+		return false
+	}
+
+	for b.used+n > cap(b.arena) {
+		b.removeOldest()
+	}
+
+	if len(b.arena)+n > cap(b.arena) {
+		b.compact()
+	}
+
+	b.keys = append(b.keys, key)
+	b.offs = append(b.offs, len(b.arena))
+	b.lens = append(b.lens, n)
+	b.lastUse = append(b.lastUse, b.nextTick())
+	b.arena = append(b.arena, val...)
+	b.used += n
+
+	return true
+}
+
+func (b *Bytes[K]) removeOldest() {
+	idx := 0
+
+	for i := range b.lastUse {
+		if b.lastUse[i] < b.lastUse[idx] {
+			idx = i
+		}
+	}
+
+	b.remove(idx)
+}
+
+func (b *Bytes[K]) remove(idx int) {
+	var key K
+
+	end := len(b.keys) - 1
+	b.used -= b.lens[idx]
+
+	b.keys[idx], b.keys[end] = b.keys[end], key
+	b.offs[idx] = b.offs[end]
+	b.lens[idx] = b.lens[end]
+	b.lastUse[idx] = b.lastUse[end]
+
+	b.keys = b.keys[:end]
+	b.offs = b.offs[:end]
+	b.lens = b.lens[:end]
+	b.lastUse = b.lastUse[:end]
+
+	if end == 0 {
+		b.arena = b.arena[:0]
+	}
+}
+
+// Move all values to the start of the arena, so that all free space is at the end.
+func (b *Bytes[K]) compact() {
+	order := make([]int, len(b.keys))
+
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortFunc(order, func(a, c int) int {
+		return b.offs[a] - b.offs[c]
+	})
+
+	off := 0
+
+	for _, idx := range order {
+		copy(b.arena[off:], b.value(idx))
+		b.offs[idx] = off
+		off += b.lens[idx]
+	}
+
+	b.arena = b.arena[:off]
+}
+
+func (b *Bytes[K]) nextTick() uint64 {
+	idx := b.tick
+	b.tick++
+	return idx
+}
@@ -0,0 +1,60 @@
+package lru
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBytes(t *testing.T) {
+	cache := NewBytes[string](10)
+
+	cache.Set("a", []byte("aaaa"))
+	cache.Set("b", []byte("bbbb"))
+
+	if v, ok := cache.Get("a"); !ok || string(v) != "aaaa" {
+		t.Fatalf("expected aaaa, got %q", v)
+	}
+
+	// Doesn't fit, so the least recently used item is evicted
+	cache.Set("c", []byte("cccc"))
+
+	if cache.Has("b") || !cache.Has("a") || !cache.Has("c") {
+		t.Fatal("expected b to be evicted")
+	}
+
+	// Fits after compaction of the hole left by b
+	cache.Set("d", []byte("dd"))
+
+	if cache.Size() != 10 || cache.Len() != 3 {
+		t.Fatalf("expected 10 bytes in 3 items, got %d bytes in %d items", cache.Size(), cache.Len())
+	}
+
+	for k, expected := range map[string]string{"a": "aaaa", "c": "cccc", "d": "dd"} {
+		if v, _ := cache.Get(k); string(v) != expected {
+			t.Fatalf("expected %q for %s, got %q", expected, k, v)
+		}
+	}
+
+	if cache.Set("e", bytes.Repeat([]byte{'e'}, 11)) {
+		t.Fatal("expected a value larger than the cache to be rejected")
+	}
+}
+
+func TestBytesReplace(t *testing.T) {
+	cache := NewBytes[int](8)
+	cache.Set(1, []byte("one"))
+
+	if !cache.Replace(1, []byte("uno!")) {
+		t.Fatal("expected existing item to be replaced")
+	}
+
+	buf := make([]byte, 0, 8)
+
+	if v, _ := cache.GetAppend(buf, 1); string(v) != "uno!" || &v[0] != &buf[:1][0] {
+		t.Fatalf("expected replaced value appended to buffer, got %q", v)
+	}
+
+	if cache.Size() != 4 {
+		t.Fatalf("expected 4 bytes, got %d", cache.Size())
+	}
+}
@@ -0,0 +1,22 @@
+package lru
+
+import "context"
+
+// Context key of a request-scoped cache, which is distinct per key and value type.
+type scopeKey[K comparable, V any] struct{}
+
+// Create a thread-safe cache for memoization within a request, and return a context holding it
+// for FromContext. A capacity of zero or less is unbounded. The cache is closed when ctx is done,
+// and then rejects all items.
+func NewRequestScoped[K comparable, V any](ctx context.Context, capacity int, opts ...Option[K, V]) (context.Context, LRU[K, V]) {
+	cache := NewThreadSafeWithOptions(capacity, append([]Option[K, V]{WithUnbounded[K, V]()}, opts...)...)
+	context.AfterFunc(ctx, func() { cache.Close() })
+
+	return context.WithValue(ctx, scopeKey[K, V]{}, cache), cache
+}
+
+// The request-scoped cache of NewRequestScoped with the same key and value types, if any.
+func FromContext[K comparable, V any](ctx context.Context) (cache LRU[K, V], ok bool) {
+	cache, ok = ctx.Value(scopeKey[K, V]{}).(LRU[K, V])
+	return
+}
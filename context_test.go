@@ -0,0 +1,33 @@
+package lru
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestScoped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cache := NewRequestScoped[string, int](ctx, 0)
+
+	cache.Set("a", 1)
+
+	if c, ok := FromContext[string, int](ctx); !ok || c != cache {
+		t.Fatal("expected the cache of the context")
+	}
+
+	if _, ok := FromContext[string, string](ctx); ok {
+		t.Fatal("expected no cache of other types")
+	}
+
+	cancel()
+	<-ctx.Done()
+
+	for cache.Set("b", 2) {
+		// The cache is closed asynchronously
+		cache.Remove("b")
+	}
+
+	if cache.Has("a") {
+		t.Fatal("expected the cache to be cleared once done")
+	}
+}
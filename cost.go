@@ -0,0 +1,50 @@
+package lru
+
+import "sync/atomic"
+
+// Evict by Greedy-Dual-Size-Frequency instead of recency, for items of varying cost to fetch. The
+// victim is the item with the lowest priority L + hits × cost / size, where size is reported by
+// WithSizer (or 1 without it), and L is the priority of the last victim when the item was last
+// used, which ages out items that aren't used anymore. The cost must not call the cache. Each
+// eviction scans all items.
+func WithCostFn[K comparable, V any](cost func(key K, val V) float64) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.cost = cost
+	}
+}
+
+// Recompute the weight of an item after a write.
+func (c *lru[K, V]) weigh(idx int) {
+	if c.cost == nil {
+		return
+	}
+
+	size := 1.0
+
+	if c.sizer != nil {
+		size = max(float64(c.sizer(c.keys[idx], c.vals[idx])), 1)
+	}
+
+	c.meta[idx].weight = c.cost(c.keys[idx], c.vals[idx]) / size
+}
+
+func (c *lru[K, V]) priority(idx int) float64 {
+	m := &c.meta[idx]
+	return m.base + float64(atomic.LoadUint64(&m.hits)+1)*m.weight
+}
+
+// Index of the unpinned item with the lowest priority, where ties are broken by recency, or -1 if
+// all items are pinned.
+func (c *lru[K, V]) cheapest() (idx int) {
+	idx = -1
+
+	var low float64
+
+	for i := c.oldest; i >= 0; i = c.newer[i] {
+		if p := c.priority(i); !c.pinned[i] && (idx < 0 || p < low) {
+			idx, low = i, p
+		}
+	}
+
+	return
+}
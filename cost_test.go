@@ -0,0 +1,51 @@
+package lru
+
+import "testing"
+
+func TestCostFn(t *testing.T) {
+	cache := NewWithOptions(3,
+		WithCostFn(func(_ string, val int) float64 {
+			return float64(val)
+		}),
+		WithSizer(func(key string, _ int) int {
+			return len(key)
+		}),
+	)
+
+	cache.Set("cheap", 1)
+	cache.Set("expensive", 100)
+	cache.Set("x", 2)
+	cache.Set("y", 3)
+
+	if cache.Has("cheap") || !cache.Has("expensive") || !cache.Has("x") {
+		t.Fatal("expected the item with the lowest cost per size to be evicted")
+	}
+
+	// Hits raise the priority of a cheap item.
+	for range 10 {
+		cache.Get("x")
+	}
+
+	cache.Set("z", 3)
+
+	if !cache.Has("x") || cache.Has("y") {
+		t.Fatal("expected frequently used items to survive")
+	}
+}
+
+func TestCostFnAging(t *testing.T) {
+	cache := NewWithOptions(2, WithCostFn(func(_ int, val int) float64 {
+		return float64(val)
+	}))
+
+	cache.Set(0, 10)
+
+	// Evicted items raise the inflation, so that new items eventually outrank an unused one.
+	for i := 1; i <= 20; i++ {
+		cache.Set(i, 1)
+	}
+
+	if cache.Has(0) {
+		t.Fatal("expected the unused expensive item to age out")
+	}
+}
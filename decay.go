@@ -0,0 +1,56 @@
+package lru
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Items halved per write while decaying.
+const decayStep = 16
+
+// Halve the hits of every item each interval, so that formerly hot items fade, as weighed by
+// WithCostFn and reported by TopKeys and Entry. The halving is spread over the following writes,
+// a few items at a time, rather than done at once.
+func WithDecayInterval[K comparable, V any](interval time.Duration) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.decay.interval = int64(interval)
+		c.decay.cursor = math.MaxInt
+	}
+}
+
+type decay struct {
+	interval int64 // Nanoseconds, or zero if never
+	next     int64 // Unix nanoseconds of the next decay, or zero if not yet scheduled
+	cursor   int   // Index of the next item to halve, or math.MaxInt if done
+}
+
+// Halve the hits of a few items, if decaying. Must hold the write lock.
+func (c *lru[K, V]) decayHits() {
+	d := &c.decay
+
+	if d.interval <= 0 {
+		return
+	}
+
+	if d.cursor >= len(c.keys) {
+		d.cursor = math.MaxInt
+		now := c.now()
+
+		if d.next == 0 {
+			d.next = now + d.interval
+		}
+
+		if now < d.next {
+			return
+		}
+
+		d.next = now + d.interval
+		d.cursor = 0
+	}
+
+	for end := min(d.cursor+decayStep, len(c.keys)); d.cursor < end; d.cursor++ {
+		m := &c.meta[d.cursor]
+		atomic.StoreUint64(&m.hits, atomic.LoadUint64(&m.hits)/2)
+	}
+}
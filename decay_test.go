@@ -0,0 +1,41 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecayInterval(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewWithOptions(40, WithClock[int, int](clock), WithDecayInterval[int, int](time.Minute))
+
+	for i := range 40 {
+		cache.Set(i, i)
+
+		for range 4 {
+			cache.Get(i)
+		}
+	}
+
+	clock.Advance(time.Minute)
+
+	// Each write halves a few items
+	cache.Get(0)
+
+	if e, _ := cache.Entry(0); e.Hits != 2 {
+		t.Fatalf("expected the first items to be halved, got %d hits", e.Hits)
+	}
+
+	if e, _ := cache.Entry(39); e.Hits != 4 {
+		t.Fatalf("expected the last items to be halved later, got %d hits", e.Hits)
+	}
+
+	cache.Get(1)
+	cache.Get(2)
+
+	for k := range cache.Iterate() {
+		if e, _ := cache.Entry(k); e.Hits > 3 {
+			t.Fatalf("expected item %d to be halved, got %d hits", k, e.Hits)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Package lru is a fast, generic and iterable LRU cache, with a thread-safe variant.
+//
+// # Order
+//
+// Items are kept in a single recency order, from least to most recently used, which decides both
+// eviction and ascending iteration. The order is deterministic, and guaranteed as follows:
+//
+//   - A new item is the most recently used, so items that are never used again are evicted in
+//     the order they were added.
+//   - Get, Touch and other uses of an existing item make it the most recently used. Peeks, such
+//     as Has, Entry and iteration, never change the order.
+//   - Replace, Upsert and other overwrites make the item the most recently used, as if it was
+//     added anew. Set, ContainsOrAdd and PeekOrAdd of an unexpired key never change the order.
+//   - Eviction due to capacity removes the least recently used item that isn't pinned or leased.
+//     Pinned items keep their position, and expired items are evicted in order like any other.
+//   - Resize evicts in the same order, one item at a time, and leaves the order of the remaining
+//     items unchanged. Growing the capacity never changes the order.
+//   - WithCostFn evicts the item of the lowest priority, and among equal priorities the least
+//     recently used.
+//
+// Recency never ties, as every change of the order moves a single item. IterateAsc, IterateDesc,
+// Keys, Values and IterateEntries follow the order, whereas Iterate visits items in no particular
+// order.
+//
+// These guarantees don't hold for thread-safe caches with WithSampledPromotion or
+// WithBufferedPromotion, whose promotions are sampled or deferred, nor with other policies of
+// WithPolicy, WithPromoteOn, WithSampledEviction or WithVictimSelector, which document their own
+// order.
+package lru
@@ -0,0 +1,78 @@
+package lru
+
+// Encoded is a cache of values held in encoded form, e.g. compressed or serialized, which trades
+// CPU for memory. Values are encoded on write and decoded on each read, so reads return a fresh
+// value that the caller owns. SizeBytes and WithCostFn account for the encoded size.
+type Encoded[K comparable, V any] struct {
+	c      LRU[K, []byte]
+	encode func(V) ([]byte, error)
+	decode func([]byte) (V, error)
+}
+
+// Create a cache of at most capacity encoded values. Not thread-safe.
+func NewEncoded[K comparable, V any](capacity int, encode func(V) ([]byte, error), decode func([]byte) (V, error), opts ...Option[K, []byte]) *Encoded[K, V] {
+	return &Encoded[K, V]{c: NewWithOptions(capacity, encodedOptions(opts)...), encode: encode, decode: decode}
+}
+
+// Create a thread-safe cache of at most capacity encoded values.
+func NewThreadSafeEncoded[K comparable, V any](capacity int, encode func(V) ([]byte, error), decode func([]byte) (V, error), opts ...Option[K, []byte]) *Encoded[K, V] {
+	return &Encoded[K, V]{c: NewThreadSafeWithOptions(capacity, encodedOptions(opts)...), encode: encode, decode: decode}
+}
+
+// Size items by their encoded values, unless the options have a sizer of their own.
+func encodedOptions[K comparable](opts []Option[K, []byte]) []Option[K, []byte] {
+	return append([]Option[K, []byte]{WithSizer(func(_ K, b []byte) int { return len(b) })}, opts...)
+}
+
+func (e *Encoded[K, V]) Len() int {
+	return e.c.Len()
+}
+
+func (e *Encoded[K, V]) Has(key K) bool {
+	return e.c.Has(key)
+}
+
+// Decoded value of a key, and mark it as most recently used. Returns ErrNotFound, ErrExpired or
+// ErrClosed on a miss, as with GetErr, or the error of decode.
+func (e *Encoded[K, V]) Get(key K) (val V, err error) {
+	b, err := e.c.GetErr(key)
+
+	if err != nil {
+		return
+	}
+
+	return e.decode(b)
+}
+
+// Add a value only if the key doesn't exist, as with the Set of LRU. Returns the error of encode,
+// without adding anything.
+func (e *Encoded[K, V]) Set(key K, val V) (ok bool, err error) {
+	b, err := e.encode(val)
+
+	if err != nil {
+		return
+	}
+
+	return e.c.Set(key, b), nil
+}
+
+// Add or overwrite a value, as with the Replace of LRU. Returns the error of encode, without
+// changing anything.
+func (e *Encoded[K, V]) Replace(key K, val V) (existed bool, err error) {
+	b, err := e.encode(val)
+
+	if err != nil {
+		return
+	}
+
+	return e.c.Replace(key, b), nil
+}
+
+func (e *Encoded[K, V]) Remove(key K) (existed bool) {
+	return e.c.Remove(key)
+}
+
+// Underlying cache of encoded values, e.g. for Stats or SizeBytes.
+func (e *Encoded[K, V]) Cache() LRU[K, []byte] {
+	return e.c
+}
@@ -0,0 +1,72 @@
+package lru
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEncoded(t *testing.T) {
+	type doc struct {
+		Body string
+	}
+
+	encode := func(d doc) ([]byte, error) {
+		var buf bytes.Buffer
+
+		zw := gzip.NewWriter(&buf)
+
+		if err := json.NewEncoder(zw).Encode(d); err != nil {
+			return nil, err
+		}
+
+		err := zw.Close()
+		return buf.Bytes(), err
+	}
+
+	decode := func(b []byte) (d doc, err error) {
+		zr, err := gzip.NewReader(bytes.NewReader(b))
+
+		if err != nil {
+			return
+		}
+
+		data, err := io.ReadAll(zr)
+
+		if err != nil {
+			return
+		}
+
+		err = json.Unmarshal(data, &d)
+		return
+	}
+
+	cache := NewThreadSafeEncoded[string](2, encode, decode)
+	body := strings.Repeat("lorem ipsum ", 1000)
+
+	if ok, err := cache.Set("a", doc{body}); !ok || err != nil {
+		t.Fatalf("expected the value to be added, got %v", err)
+	}
+
+	if d, err := cache.Get("a"); err != nil || d.Body != body {
+		t.Fatalf("expected the decoded value, got %v", err)
+	}
+
+	if size := cache.Cache().SizeBytes(); size > int64(len(body)) {
+		t.Fatalf("expected the compressed size to be accounted, got %d bytes", size)
+	}
+
+	if _, err := cache.Get("b"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	cache.c.Replace("b", []byte("corrupt"))
+
+	if _, err := cache.Get("b"); err == nil {
+		t.Fatal("expected a decode error")
+	}
+}
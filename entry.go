@@ -0,0 +1,116 @@
+package lru
+
+import (
+	"iter"
+	"sync/atomic"
+	"time"
+)
+
+// Metadata of an item, as returned by Entry.
+type EntryInfo[K comparable, V any] struct {
+	Key      K
+	Value    V
+	Created  time.Time // Zero unless WithEntryTimes or WithExpireAfterAccess is used
+	Accessed time.Time // Zero unless WithEntryTimes or WithExpireAfterAccess is used, or if never accessed
+	Expires  time.Time // Zero if never, otherwise the earliest of write and idle expiry
+	Hits     uint64
+	Version  uint64 // Changes on each write, as used by CompareAndSwap
+
+	Age time.Duration // Time since Created, or zero unless WithEntryTimes is used
+	TTL time.Duration // Remaining time until Expires, or zero if never
+}
+
+// Per-item metadata. Hits and access times are updated atomically, as they may change during
+// concurrent reads.
+type entryMeta struct {
+	created  int64
+	accessed int64
+	hits     uint64
+	version  uint64
+	base     float64 // Inflation when last used, with WithCostFn
+	weight   float64 // Cost per size, with WithCostFn
+	stamp    uint64  // Last use, with WithSampledEviction
+	mark     uint64  // Hits when last queued, with PolicyS3FIFO
+	small    bool    // Whether in the small queue, with PolicyS3FIFO
+}
+
+// Metadata of an item, without affecting recency.
+func (c *lru[K, V]) Entry(key K) (info EntryInfo[K, V], ok bool) {
+	idx, ok := c.index(key)
+
+	if !ok || !c.alive(idx) {
+		return info, false
+	}
+
+	return c.entryInfo(idx, c.now()), true
+}
+
+// Iterate the metadata of all items in ascending order.
+func (c *lru[K, V]) IterateEntries() iter.Seq[EntryInfo[K, V]] {
+	return func(yield func(EntryInfo[K, V]) bool) {
+		now := c.now()
+
+		for idx := range c.ascending() {
+			if !yield(c.entryInfo(idx, now)) {
+				return
+			}
+		}
+	}
+}
+
+func (c *lru[K, V]) entryInfo(idx int, now int64) (info EntryInfo[K, V]) {
+	m := &c.meta[idx]
+
+	info = EntryInfo[K, V]{
+		Key:      c.keys[idx],
+		Value:    c.copy(c.vals[idx]),
+		Created:  unixTime(m.created),
+		Accessed: unixTime(atomic.LoadInt64(&m.accessed)),
+		Expires:  unixTime(c.deadline(idx)),
+		Hits:     atomic.LoadUint64(&m.hits),
+		Version:  m.version,
+	}
+
+	if m.created != 0 {
+		info.Age = time.Duration(now - m.created)
+	}
+
+	if exp := c.deadline(idx); exp != 0 {
+		info.TTL = time.Duration(exp - now)
+	}
+
+	return
+}
+
+func (c *lru[K, V]) newMeta() (m entryMeta) {
+	m.version = c.nextVersion()
+	m.base = c.inflation
+
+	if c.samples > 0 {
+		m.stamp = c.nextStamp()
+	}
+
+	if c.entryTimes || c.idle > 0 {
+		m.created = c.now()
+	}
+
+	return
+}
+
+func (c *lru[K, V]) accessed(idx int) {
+	m := &c.meta[idx]
+	atomic.AddUint64(&m.hits, 1)
+
+	if c.entryTimes || c.idle > 0 {
+		atomic.StoreInt64(&m.accessed, c.now())
+	}
+}
+
+// Time of Unix nanoseconds, or the zero time if zero.
+func unixTime(nsec int64) time.Time {
+	if nsec == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, nsec)
+}
@@ -0,0 +1,80 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntry(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewWithOptions(2, WithClock[int, int](clock), WithEntryTimes[int, int]())
+
+	created := clock.Now()
+	cache.Set(1, 1)
+	clock.Advance(time.Second)
+	cache.Get(1)
+	cache.Get(1)
+
+	info, ok := cache.Entry(1)
+
+	if !ok {
+		t.Fatal("expected entry to exist")
+	}
+
+	if !info.Created.Equal(created) || !info.Accessed.Equal(clock.Now()) {
+		t.Fatalf("unexpected times: %+v", info)
+	}
+
+	if info.Key != 1 || info.Value != 1 || info.Hits != 2 || !info.Expires.IsZero() {
+		t.Fatalf("unexpected entry: %+v", info)
+	}
+
+	cache.Set(2, 2)
+	cache.Entry(1)
+	cache.Set(3, 3)
+
+	if _, ok := cache.Entry(1); ok {
+		t.Fatal("expected Entry to not affect recency")
+	}
+}
+
+func TestEntryWithoutTimes(t *testing.T) {
+	cache := New[int, int](2)
+	cache.Set(1, 1)
+	cache.Get(1)
+
+	info, _ := cache.Entry(1)
+
+	if !info.Created.IsZero() || !info.Accessed.IsZero() || info.Hits != 1 {
+		t.Fatalf("expected only hits to be recorded, got %+v", info)
+	}
+}
+
+func TestIterateEntries(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewWithOptions(4, WithClock[int, int](clock), WithEntryTimes[int, int]())
+
+	cache.Set(1, 1)
+	clock.Advance(time.Second)
+	_, _ = cache.GetOrSetTTL(2, time.Minute, func(k int) (int, error) { return k, nil })
+	clock.Advance(time.Second)
+	cache.Get(1)
+
+	var entries []EntryInfo[int, int]
+
+	for e := range cache.IterateEntries() {
+		entries = append(entries, e)
+	}
+
+	if len(entries) != 2 || entries[0].Key != 2 || entries[1].Key != 1 {
+		t.Fatalf("expected entries in ascending order, got %+v", entries)
+	}
+
+	if entries[0].Age != time.Second || entries[0].TTL != time.Minute-time.Second {
+		t.Fatalf("unexpected age or ttl: %+v", entries[0])
+	}
+
+	if entries[1].Age != 2*time.Second || entries[1].TTL != 0 || entries[1].Hits != 1 {
+		t.Fatalf("unexpected age, ttl or hits: %+v", entries[1])
+	}
+}
@@ -0,0 +1,54 @@
+package lru
+
+import "errors"
+
+var (
+	// No item exists with the key.
+	ErrNotFound = errors.New("lru: not found")
+
+	// The item of the key has expired, and is yet to be removed.
+	ErrExpired = errors.New("lru: expired")
+
+	// The cache is closed.
+	ErrClosed = errors.New("lru: closed")
+
+	// A setter call of GetOrSet or its variants was refused by WithLoaderLimit, and no stale value
+	// is available.
+	ErrLoaderThrottled = errors.New("lru: loader throttled")
+
+	// An internal invariant of the cache is violated, as reported by Validate.
+	ErrCorrupt = errors.New("lru: corrupt")
+)
+
+func (c *lru[K, V]) GetErr(key K) (val V, err error) {
+	if val, ok := c.Get(key); ok {
+		return val, nil
+	}
+
+	return val, c.missErr(key)
+}
+
+// GetErr implements LRU.
+func (t *threadsafe[K, V]) GetErr(key K) (val V, err error) {
+	if val, ok := t.Get(key); ok {
+		return val, nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return val, t.lru.missErr(key)
+}
+
+// The reason of a miss.
+func (c *lru[K, V]) missErr(key K) error {
+	if c.closed {
+		return ErrClosed
+	}
+
+	if idx, ok := c.index(key); ok && !c.alive(idx) {
+		return ErrExpired
+	}
+
+	return ErrNotFound
+}
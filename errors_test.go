@@ -0,0 +1,33 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetErr(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewThreadSafeWithOptions(8, WithClock[int, int](clock))
+
+	cache.Set(1, 1)
+	cache.SetWithTTL(2, 2, time.Second)
+	clock.Advance(time.Second)
+
+	if v, err := cache.GetErr(1); err != nil || v != 1 {
+		t.Fatalf("expected a hit, got %d and %v", v, err)
+	}
+
+	if _, err := cache.GetErr(2); err != ErrExpired {
+		t.Fatalf("expected ErrExpired, got %v", err)
+	}
+
+	if _, err := cache.GetErr(3); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	cache.Close()
+
+	if _, err := cache.GetErr(1); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package lru
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kind of cache event.
+type EventKind uint8
+
+const (
+	EventHit    EventKind = iota + 1 // A Get found an item
+	EventMiss                        // A Get didn't find an item
+	EventInsert                      // A new item was added
+	EventEvict                       // An item was evicted due to capacity
+)
+
+var eventKinds = [...]string{
+	EventHit:    "hit",
+	EventMiss:   "miss",
+	EventInsert: "insert",
+	EventEvict:  "evict",
+}
+
+func (k EventKind) String() string {
+	if int(k) < len(eventKinds) && eventKinds[k] != "" {
+		return eventKinds[k]
+	}
+
+	return fmt.Sprintf("EventKind(%d)", k)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k EventKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *EventKind) UnmarshalText(b []byte) error {
+	for i, s := range eventKinds {
+		if s != "" && s == string(b) {
+			*k = EventKind(i)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("lru: unknown event kind %q", b)
+}
+
+// A cache event, as emitted to WithEvents.
+type Event[K comparable] struct {
+	Kind EventKind `json:"kind"`
+	Key  K         `json:"key"`
+	Time time.Time `json:"time"`
+}
+
+func (c *lru[K, V]) emit(kind EventKind, key K) {
+	if c.events != nil {
+		c.events(Event[K]{
+			Kind: kind,
+			Key:  key,
+			Time: c.clock.Now(),
+		})
+	}
+}
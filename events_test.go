@@ -0,0 +1,69 @@
+package lru
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	var kinds []EventKind
+
+	cache := NewWithOptions(1, WithEvents[int, int](func(e Event[int]) {
+		kinds = append(kinds, e.Kind)
+	}))
+
+	cache.Set(1, 1)
+	cache.Get(1)
+	cache.Get(2)
+	cache.Set(2, 2)
+
+	expected := []EventKind{EventInsert, EventHit, EventMiss, EventEvict, EventInsert}
+
+	if len(kinds) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, kinds)
+	}
+
+	for i := range expected {
+		if kinds[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, kinds)
+		}
+	}
+}
+
+func TestEventKindText(t *testing.T) {
+	data, err := json.Marshal(Event[string]{Kind: EventEvict, Key: "a"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var e Event[string]
+
+	if err = json.Unmarshal(data, &e); err != nil {
+		t.Fatal(err)
+	}
+
+	if e.Kind != EventEvict || e.Key != "a" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+}
+
+func TestInsertedAndHit(t *testing.T) {
+	var inserted, hits []int
+
+	cache := NewWithOptions(2,
+		WithInserted(func(k int, _ int) { inserted = append(inserted, k) }),
+		WithHit(func(k int, _ int) { hits = append(hits, k) }),
+	)
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Replace(1, 10)
+	cache.Get(1)
+	cache.Get(3)
+
+	if !slices.Equal(inserted, []int{1, 2}) || !slices.Equal(hits, []int{1}) {
+		t.Fatalf("unexpected inserts %v and hits %v", inserted, hits)
+	}
+}
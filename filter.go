@@ -0,0 +1,119 @@
+package lru
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+// Maintain a bloom filter of all keys, with the given false positive rate (e.g. 0.01), so that
+// MightContain can rule out most missing keys without a lookup. The filter is rebuilt as evicted
+// and removed keys pile up, which costs a scan of all keys about every capacity/2 removals.
+func WithFilter[K comparable, V any](falsePositiveRate float64) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.filter.rate = min(max(falsePositiveRate, 1e-9), 0.5)
+		c.filter.seed = maphash.MakeSeed()
+		c.filter.reset(c.capacity)
+	}
+}
+
+// Whether an item might exist. False means that it definitely doesn't exist, while true means
+// that it exists, with the false positive rate of WithFilter, or has expired. Without WithFilter,
+// this equals Has. Never affects recency or stats.
+func (c *lru[K, V]) MightContain(key K) bool {
+	if c.filter.bits == nil {
+		return c.Has(key)
+	}
+
+	return c.filter.has(c.resolve(key))
+}
+
+// MightContain implements LRU.
+func (t *threadsafe[K, V]) MightContain(key K) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.MightContain(key)
+}
+
+// Rebuild the filter from all keys once too many of its keys are removed, or once it holds more
+// keys than it was sized for.
+func (c *lru[K, V]) refilter() {
+	f := &c.filter
+
+	if f.bits == nil || (f.stale <= f.n/2 && f.added-f.stale <= f.n) {
+		return
+	}
+
+	n := max(c.capacity, len(c.keys))
+
+	if len(c.keys) > f.n {
+		n = 2 * len(c.keys)
+	}
+
+	f.reset(n)
+
+	for _, key := range c.keys {
+		f.add(key)
+	}
+}
+
+// Bloom filter of keys. Removed keys remain until the filter is rebuilt.
+type filter[K comparable] struct {
+	seed  maphash.Seed
+	bits  []uint64
+	k     uint64  // Hashes per key
+	n     int     // Number of keys the filter is sized for
+	rate  float64 // False positive rate at n keys
+	added int     // Keys added since reset
+	stale int     // Keys removed since reset
+}
+
+func (f *filter[K]) reset(n int) {
+	n = max(n, 64)
+	m := math.Ceil(-float64(n) * math.Log(f.rate) / (math.Ln2 * math.Ln2))
+	words := (int(m) + 63) / 64
+
+	if f.n == n && len(f.bits) == words {
+		clear(f.bits)
+	} else {
+		f.bits = make([]uint64, words)
+	}
+
+	f.k = max(1, uint64(math.Round(float64(words*64)/float64(n)*math.Ln2)))
+	f.n = n
+	f.added = 0
+	f.stale = 0
+}
+
+func (f *filter[K]) add(key K) {
+	h1, h2 := f.hash(key)
+	m := uint64(len(f.bits) * 64)
+
+	for i := range f.k {
+		b := (h1 + i*h2) % m
+		f.bits[b/64] |= 1 << (b % 64)
+	}
+
+	f.added++
+}
+
+func (f *filter[K]) has(key K) bool {
+	h1, h2 := f.hash(key)
+	m := uint64(len(f.bits) * 64)
+
+	for i := range f.k {
+		b := (h1 + i*h2) % m
+
+		if f.bits[b/64]&(1<<(b%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Two hashes for double hashing, derived from a single one.
+func (f *filter[K]) hash(key K) (h1, h2 uint64) {
+	h := hashKey(f.seed, key)
+	return h, h>>32 | h<<32 | 1
+}
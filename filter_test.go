@@ -0,0 +1,50 @@
+package lru
+
+import "testing"
+
+func TestFilter(t *testing.T) {
+	cache := NewWithOptions(100, WithFilter[int, int](0.01))
+
+	for i := range 1000 {
+		cache.Set(i, i)
+	}
+
+	for i := 900; i < 1000; i++ {
+		if !cache.MightContain(i) {
+			t.Fatalf("expected %d to be in the filter", i)
+		}
+	}
+
+	var positives int
+
+	for i := 1000; i < 11000; i++ {
+		if cache.MightContain(i) {
+			positives++
+		}
+	}
+
+	// Allow some slack above the configured rate, including keys evicted since last rebuild.
+	if positives > 500 {
+		t.Fatalf("expected few false positives, got %d of 10000", positives)
+	}
+
+	cache.Remove(999)
+
+	if cache.Reset(); cache.MightContain(998) {
+		t.Fatal("expected an empty filter after reset")
+	}
+}
+
+func TestFilterUnbounded(t *testing.T) {
+	cache := NewWithOptions(0, WithFilter[int, int](0.01), WithUnbounded[int, int]())
+
+	for i := range 1000 {
+		cache.Set(i, i)
+	}
+
+	for i := range 1000 {
+		if !cache.MightContain(i) {
+			t.Fatalf("expected %d to be in the filter", i)
+		}
+	}
+}
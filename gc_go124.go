@@ -0,0 +1,12 @@
+//go:build go1.24
+
+package lru
+
+import "runtime"
+
+// Signal after the next garbage collection, and then watch for the next one, until closed.
+func (m *MemoryController) watchGC(gc chan<- struct{}) {
+	runtime.AddCleanup(new(gcSentinel), func(gc chan<- struct{}) {
+		m.signalGC(gc)
+	}, gc)
+}
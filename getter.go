@@ -0,0 +1,246 @@
+package lru
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Getter loads a value by key, e.g. from a database.
+type Getter[K comparable, V any] interface {
+	Get(ctx context.Context, key K) (V, error)
+}
+
+// GetterFunc is a function implementing Getter.
+type GetterFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// Get implements Getter.
+func (f GetterFunc[K, V]) Get(ctx context.Context, key K) (V, error) {
+	return f(ctx, key)
+}
+
+// GetterOption configures a read-through cache on creation.
+type GetterOption func(*getterConfig)
+
+type getterConfig struct {
+	timeout    time.Duration
+	serveStale bool
+	retries    int
+	backoff    time.Duration
+}
+
+// Stop waiting for a load after a timeout, and return context.DeadlineExceeded. The load continues
+// in the background, without the cancellation of the first caller's context, and its value is
+// stored once loaded.
+func WithLoadTimeout(timeout time.Duration) GetterOption {
+	return func(c *getterConfig) {
+		c.timeout = timeout
+	}
+}
+
+// Return the expired value of a key on a load timeout instead of an error, if the value is yet to
+// be removed from the cache. Requires WithLoadTimeout.
+func WithServeStale() GetterOption {
+	return func(c *getterConfig) {
+		c.serveStale = true
+	}
+}
+
+// Retry a failed load up to n times, after a backoff that doubles with each retry, before
+// returning the error to every caller sharing the load. Context errors aren't retried.
+func WithLoaderRetry(n int, backoff time.Duration) GetterOption {
+	return func(c *getterConfig) {
+		c.retries = n
+		c.backoff = backoff
+	}
+}
+
+// A read-through cache in front of a loader.
+type readThrough[K comparable, V any] struct {
+	getterConfig
+	cache  LRU[K, V]
+	loader Getter[K, V]
+	mu     sync.Mutex
+	calls  map[K]*loadCall[V]
+}
+
+type loadCall[V any] struct {
+	done     chan struct{}
+	val      V
+	err      error
+	panicked any // Recovered panic of the loader, which is returned as an error to other callers
+}
+
+// Create a read-through cache, which loads misses with the loader outside of any cache lock.
+// Concurrent misses of the same key share a single load, which uses the context of the first
+// caller. Misses of other keys are never blocked by a load. Loader errors are remembered if the
+// cache uses WithNegativeTTL. A loader panic is returned as an error to the other callers, and
+// panics again in the caller that ran the load, without caching anything.
+func NewGetter[K comparable, V any](cache LRU[K, V], loader Getter[K, V], opts ...GetterOption) Getter[K, V] {
+	r := &readThrough[K, V]{
+		cache:  cache,
+		loader: loader,
+		calls:  make(map[K]*loadCall[V]),
+	}
+
+	for _, opt := range opts {
+		opt(&r.getterConfig)
+	}
+
+	return r
+}
+
+// Get implements Getter.
+func (r *readThrough[K, V]) Get(ctx context.Context, key K) (val V, err error) {
+	var ok bool
+
+	if val, ok = r.cache.Get(key); ok {
+		return
+	}
+
+	if n, ok := r.cache.(interface{ rememberedErr(K) error }); ok {
+		if err = n.rememberedErr(key); err != nil {
+			return
+		}
+	}
+
+	r.mu.Lock()
+
+	if c, ok := r.calls[key]; ok {
+		r.mu.Unlock()
+		return r.wait(ctx, key, c)
+	}
+
+	c := &loadCall[V]{done: make(chan struct{})}
+	r.calls[key] = c
+	r.mu.Unlock()
+
+	if r.timeout > 0 {
+		go r.load(context.WithoutCancel(ctx), key, c)
+		return r.wait(ctx, key, c)
+	}
+
+	if r.load(ctx, key, c); c.panicked != nil {
+		panic(c.panicked)
+	}
+
+	return c.val, c.err
+}
+
+// Wait for a shared load to finish, or for the context or load timeout.
+func (r *readThrough[K, V]) wait(ctx context.Context, key K, c *loadCall[V]) (val V, err error) {
+	var timeout <-chan time.Time
+
+	if r.timeout > 0 {
+		timer := time.NewTimer(r.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		return val, ctx.Err()
+	case <-timeout:
+	}
+
+	if r.serveStale {
+		if s, ok := r.cache.(interface{ staleValue(K) (V, bool) }); ok {
+			if val, ok = s.staleValue(key); ok {
+				return
+			}
+		}
+	}
+
+	return val, context.DeadlineExceeded
+}
+
+// Cache that stores a loaded value or error directly, without a loader limit.
+type loadStorer[K comparable, V any] interface {
+	storeLoaded(key K, val V, ttl time.Duration, err error)
+}
+
+func (r *readThrough[K, V]) load(ctx context.Context, key K, c *loadCall[V]) {
+	// Store the value, or remember the error, with the cache's own rules. The load is already done,
+	// so the loader limit is skipped where possible.
+	if c.val, c.panicked, c.err = r.call(ctx, key); c.panicked == nil {
+		if s, ok := r.cache.(loadStorer[K, V]); ok {
+			s.storeLoaded(key, c.val, 0, c.err)
+		} else {
+			r.cache.GetOrSet(key, func(K) (V, error) {
+				return c.val, c.err
+			}, ForceRefresh())
+		}
+	}
+
+	r.mu.Lock()
+	delete(r.calls, key)
+	r.mu.Unlock()
+	close(c.done)
+}
+
+// Call the loader with retries, and recover any panic.
+func (r *readThrough[K, V]) call(ctx context.Context, key K) (val V, panicked any, err error) {
+	defer func() {
+		if panicked = recover(); panicked != nil {
+			err = fmt.Errorf("lru: loader panicked: %v", panicked)
+		}
+	}()
+
+	for retry := 0; ; retry++ {
+		if val, err = r.loader.Get(ctx, key); err == nil || retry >= r.retries || ctx.Err() != nil {
+			return
+		}
+
+		timer := time.NewTimer(r.backoff << retry)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// Store a loaded value, or remember the error of its loader.
+func (t *threadsafe[K, V]) storeLoaded(key K, val V, ttl time.Duration, err error) {
+	t.lock()
+	defer t.unlock()
+
+	t.lru.storeLoaded(key, val, ttl, err)
+}
+
+// A remembered, unexpired error of a key.
+func (c *lru[K, V]) rememberedErr(key K) error {
+	return c.negativeErr(key)
+}
+
+// A remembered, unexpired error of a key.
+func (t *threadsafe[K, V]) rememberedErr(key K) error {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.negativeErr(key)
+}
+
+// The value of an item whether expired or not, if it's yet to be removed.
+func (c *lru[K, V]) staleValue(key K) (val V, ok bool) {
+	idx, ok := c.index(key)
+
+	if ok {
+		val = c.copy(c.vals[idx])
+	}
+
+	return
+}
+
+// The value of an item whether expired or not, if it's yet to be removed.
+func (t *threadsafe[K, V]) staleValue(key K) (val V, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.staleValue(key)
+}
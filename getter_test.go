@@ -0,0 +1,172 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetter(t *testing.T) {
+	var calls atomic.Int32
+
+	errNotFound := errors.New("not found")
+	release := make(chan struct{})
+	cache := NewThreadSafeWithOptions(8, WithNegativeTTL[int, int](time.Minute, errNotFound))
+	getter := NewGetter(cache, GetterFunc[int, int](func(_ context.Context, key int) (int, error) {
+		calls.Add(1)
+		<-release
+
+		if key < 0 {
+			return 0, errNotFound
+		}
+
+		return key * 10, nil
+	}))
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if v, err := getter.Get(context.Background(), 1); err != nil || v != 10 {
+				t.Errorf("expected a loaded value, got %d and %v", v, err)
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected concurrent misses to share a load, got %d loads", n)
+	}
+
+	for range 2 {
+		if _, err := getter.Get(context.Background(), -1); err != errNotFound {
+			t.Fatalf("expected the loader error, got %v", err)
+		}
+	}
+
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected a remembered error to skip the loader, got %d loads", n)
+	}
+}
+
+func TestGetterLoadTimeout(t *testing.T) {
+	release := make(chan struct{})
+	clock := newFakeClock()
+	cache := NewThreadSafeWithOptions(8, WithClock[int, int](clock))
+	loader := GetterFunc[int, int](func(ctx context.Context, key int) (int, error) {
+		<-release
+		return key * 10, ctx.Err()
+	})
+
+	getter := NewGetter(cache, loader, WithLoadTimeout(10*time.Millisecond))
+	stale := NewGetter(cache, loader, WithLoadTimeout(10*time.Millisecond), WithServeStale())
+
+	cache.SetWithTTL(1, 1, time.Second)
+	clock.Advance(time.Second)
+
+	if _, err := getter.Get(context.Background(), 1); err != context.DeadlineExceeded {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if v, err := stale.Get(ctx, 1); err != nil || v != 1 {
+		t.Fatalf("expected the stale value, got %d and %v", v, err)
+	}
+
+	if _, err := stale.Get(context.Background(), 2); err != context.DeadlineExceeded {
+		t.Fatalf("expected a timeout without a stale value, got %v", err)
+	}
+
+	// The loads continue despite the canceled context, and store their values
+	cancel()
+	close(release)
+
+	for !cache.Has(1) || !cache.Has(2) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if v, err := stale.Get(context.Background(), 1); err != nil || v != 10 {
+		t.Fatalf("expected the loaded value, got %d and %v", v, err)
+	}
+}
+
+func TestGetterPanic(t *testing.T) {
+	release := make(chan struct{})
+	cache := NewThreadSafe[int, int](8)
+	getter := NewGetter(cache, GetterFunc[int, int](func(context.Context, int) (int, error) {
+		<-release
+		panic("boom")
+	}))
+
+	errs := make(chan error)
+	done := make(chan struct{})
+
+	go func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the loading caller to panic")
+			}
+
+			close(done)
+		}()
+
+		getter.Get(context.Background(), 1)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	go func() {
+		_, err := getter.Get(context.Background(), 1)
+		errs <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	if err := <-errs; err == nil {
+		t.Fatal("expected a waiting caller to get an error")
+	}
+
+	<-done
+
+	if cache.Has(1) {
+		t.Fatal("expected nothing to be cached")
+	}
+}
+
+func TestGetterRetry(t *testing.T) {
+	var calls atomic.Int32
+
+	cache := NewThreadSafe[int, int](8)
+	loader := GetterFunc[int, int](func(_ context.Context, key int) (int, error) {
+		if calls.Add(1) < 3 {
+			return 0, errors.New("transient")
+		}
+
+		return key, nil
+	})
+
+	getter := NewGetter(cache, loader, WithLoaderRetry(2, time.Millisecond))
+
+	if v, err := getter.Get(context.Background(), 1); err != nil || v != 1 || calls.Load() != 3 {
+		t.Fatalf("expected the load to succeed on the last retry, got %d and %v", v, err)
+	}
+
+	calls.Store(0)
+	getter = NewGetter(cache, loader, WithLoaderRetry(1, time.Millisecond))
+
+	if _, err := getter.Get(context.Background(), 2); err == nil {
+		t.Fatal("expected the error once retries are exhausted")
+	}
+}
@@ -0,0 +1,93 @@
+//go:build !go1.24
+
+package lru
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// Hash of a comparable key. Without maphash.Comparable, keys are hashed by reflection with the
+// same equality as ==: pointers and channels by address, interfaces by dynamic type and value,
+// structs by their non-blank fields, and -0 as 0. NaN never equals itself, so its hash is
+// irrelevant.
+func hashKey[K comparable](seed maphash.Seed, key K) uint64 {
+	if s, ok := any(key).(string); ok {
+		return maphash.String(seed, s)
+	}
+
+	var h maphash.Hash
+	h.SetSeed(seed)
+	writeHash(&h, reflect.ValueOf(&key).Elem())
+
+	return h.Sum64()
+}
+
+func writeHash(h *maphash.Hash, v reflect.Value) {
+	var buf [8]byte
+
+	word := func(u uint64) {
+		binary.LittleEndian.PutUint64(buf[:], u)
+		h.Write(buf[:])
+	}
+
+	float := func(f float64) {
+		if f == 0 {
+			f = 0
+		}
+
+		word(math.Float64bits(f))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			word(1)
+		} else {
+			word(0)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		word(uint64(v.Int()))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		word(v.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		float(v.Float())
+
+	case reflect.Complex64, reflect.Complex128:
+		float(real(v.Complex()))
+		float(imag(v.Complex()))
+
+	case reflect.String:
+		word(uint64(v.Len()))
+		h.WriteString(v.String())
+
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Chan:
+		word(uint64(v.Pointer()))
+
+	case reflect.Interface:
+		if v.IsNil() {
+			word(0)
+			return
+		}
+
+		h.WriteString(v.Elem().Type().String())
+		writeHash(h, v.Elem())
+
+	case reflect.Array:
+		for i := range v.Len() {
+			writeHash(h, v.Index(i))
+		}
+
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).Name != "_" {
+				writeHash(h, v.Field(i))
+			}
+		}
+	}
+}
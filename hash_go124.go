@@ -0,0 +1,10 @@
+//go:build go1.24
+
+package lru
+
+import "hash/maphash"
+
+// Hash of a comparable key.
+func hashKey[K comparable](seed maphash.Seed, key K) uint64 {
+	return maphash.Comparable(seed, key)
+}
@@ -0,0 +1,113 @@
+package lru
+
+import "iter"
+
+// Hashed is a cache for keys that aren't comparable, e.g. byte slices or structs containing
+// slices. Items are addressed by the hash of their key, and items with colliding hashes evict
+// each other. Not thread-safe.
+type Hashed[K any, V any] struct {
+	c    LRU[uint64, hashedItem[K, V]]
+	hash func(K) uint64
+	eq   func(K, K) bool
+}
+
+type hashedItem[K any, V any] struct {
+	key K
+	val V
+}
+
+// Create a cache for keys that aren't comparable, using the provided hash and equality functions.
+func NewHashed[K any, V any](capacity int, hash func(K) uint64, eq func(K, K) bool, evicted ...func(key K, val V)) *Hashed[K, V] {
+	h := &Hashed[K, V]{
+		hash: hash,
+		eq:   eq,
+	}
+
+	if len(evicted) > 0 && evicted[0] != nil {
+		cb := evicted[0]
+		h.c = New(capacity, func(_ uint64, item hashedItem[K, V]) {
+			cb(item.key, item.val)
+		})
+	} else {
+		h.c = New[uint64, hashedItem[K, V]](capacity)
+	}
+
+	return h
+}
+
+func (h *Hashed[K, V]) Len() int {
+	return h.c.Len()
+}
+
+func (h *Hashed[K, V]) Has(key K) (ok bool) {
+	info, ok := h.c.Entry(h.hash(key))
+	return ok && h.eq(info.Value.key, key)
+}
+
+func (h *Hashed[K, V]) Get(key K) (val V, ok bool) {
+	hash := h.hash(key)
+
+	// Don't promote a colliding item
+	if info, found := h.c.Entry(hash); found && !h.eq(info.Value.key, key) {
+		return
+	}
+
+	item, ok := h.c.Get(hash)
+	return item.val, ok
+}
+
+func (h *Hashed[K, V]) GetOrSet(key K, setter func(K) (V, error)) (val V, err error) {
+	var ok bool
+
+	if val, ok = h.Get(key); ok {
+		return
+	}
+
+	if val, err = setter(key); err == nil {
+		h.c.Replace(h.hash(key), hashedItem[K, V]{key: key, val: val})
+	}
+
+	return
+}
+
+// Add an item only if the key doesn't exist. An item with a colliding hash is evicted.
+func (h *Hashed[K, V]) Set(key K, val V) (ok bool) {
+	if h.Has(key) {
+		return false
+	}
+
+	h.c.Replace(h.hash(key), hashedItem[K, V]{key: key, val: val})
+	return true
+}
+
+// Add or overwrite an item. An overwritten value, or an item with a colliding hash, is notified
+// as evicted.
+func (h *Hashed[K, V]) Replace(key K, val V) (existed bool) {
+	existed = h.Has(key)
+	h.c.Replace(h.hash(key), hashedItem[K, V]{key: key, val: val})
+	return
+}
+
+func (h *Hashed[K, V]) Remove(key K) (existed bool) {
+	if !h.Has(key) {
+		return false
+	}
+
+	return h.c.Remove(h.hash(key))
+}
+
+// Iterate all items in no particular order.
+func (h *Hashed[K, V]) Iterate() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, item := range h.c.Iterate() {
+			if !yield(item.key, item.val) {
+				return
+			}
+		}
+	}
+}
+
+// Clear cache without notice.
+func (h *Hashed[K, V]) Reset() {
+	h.c.Reset()
+}
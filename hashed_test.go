@@ -0,0 +1,47 @@
+package lru
+
+import (
+	"bytes"
+	"fmt"
+	"hash/maphash"
+	"testing"
+)
+
+func ExampleHashed() {
+	seed := maphash.MakeSeed()
+	cache := NewHashed[[]byte, int](8, func(key []byte) uint64 {
+		return maphash.Bytes(seed, key)
+	}, bytes.Equal)
+
+	cache.Set([]byte("foo"), 1)
+
+	fmt.Println(cache.Get([]byte("foo")))
+	fmt.Println(cache.Get([]byte("bar")))
+
+	// Output:
+	// 1 true
+	// 0 false
+}
+
+func TestHashedCollision(t *testing.T) {
+	var evicted []string
+
+	// All keys collide
+	cache := NewHashed(8, func(string) uint64 { return 0 }, func(a, b string) bool { return a == b }, func(k string, _ int) {
+		evicted = append(evicted, k)
+	})
+
+	cache.Set("a", 1)
+
+	if _, ok := cache.Get("b"); ok || cache.Has("b") || cache.Remove("b") {
+		t.Fatal("expected a colliding key to miss")
+	}
+
+	if !cache.Set("b", 2) || cache.Has("a") || len(evicted) != 1 || evicted[0] != "a" {
+		t.Fatalf("expected a colliding item to be evicted, got %v", evicted)
+	}
+
+	if v, ok := cache.Get("b"); !ok || v != 2 {
+		t.Fatalf("expected the new item, got %d", v)
+	}
+}
@@ -0,0 +1,53 @@
+package lru
+
+// Publish each key that is set or removed locally, e.g. to other instances through a message
+// broker. Evictions, expirations and loaded values aren't published. The function is called
+// after releasing the lock of a thread-safe cache.
+func WithChanged[K comparable, V any](changed func(key K)) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.changed = changed
+	}
+}
+
+// Remove each key received from a channel, e.g. invalidations from other instances, until the
+// channel is closed. Removals are notified as evicted, as with Remove, but aren't published by
+// WithChanged. Panics unless the cache is thread-safe, as keys are removed from another goroutine.
+func AttachInvalidator[K comparable, V any](c LRU[K, V], ch <-chan K) {
+	t, ok := c.(*threadsafe[K, V])
+
+	if !ok {
+		panic("lru: AttachInvalidator requires a thread-safe cache")
+	}
+
+	go func() {
+		for key := range ch {
+			t.invalidate(key)
+		}
+	}()
+}
+
+func (c *lru[K, V]) change(key K) {
+	if c.changed != nil {
+		c.changed(key)
+	}
+}
+
+func (c *lru[K, V]) invalidate(key K) {
+	c.removeKey(key)
+}
+
+func (t *threadsafe[K, V]) invalidate(key K) {
+	t.lock()
+	defer t.unlock()
+
+	t.lru.invalidate(key)
+}
+
+// Queue a changed key for publishing once the write lock is released.
+func (t *threadsafe[K, V]) queueChanged(key K) {
+	t.queue = append(t.queue, notification[K, V]{fn: t.publish, key: key})
+}
+
+func (t *threadsafe[K, V]) publish(key K, _ V) {
+	t.changed(key)
+}
@@ -0,0 +1,54 @@
+package lru
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestInvalidation(t *testing.T) {
+	var changed []int
+
+	invalidations := make(chan int)
+	cache := NewThreadSafeWithOptions(8, WithChanged[int, int](func(key int) {
+		changed = append(changed, key)
+	}))
+
+	AttachInvalidator(cache, invalidations)
+	defer close(invalidations)
+
+	cache.Set(1, 1)
+	cache.Replace(2, 2)
+	cache.Remove(1)
+	cache.Remove(3)
+
+	if !slices.Equal(changed, []int{1, 2, 1}) {
+		t.Fatalf("expected local changes to be published, got %v", changed)
+	}
+
+	invalidations <- 2
+
+	for range 100 {
+		if !cache.Has(2) {
+			if len(changed) != 3 {
+				t.Fatal("expected invalidations to not be published")
+			}
+
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("expected an invalidated key to be removed")
+}
+
+func TestInvalidatorNotThreadSafe(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a cache that isn't thread-safe to be refused")
+		}
+	}()
+
+	AttachInvalidator(New[int, int](8), make(chan int))
+}
@@ -0,0 +1,61 @@
+package lru
+
+import "time"
+
+// Clear the cache, which then rejects all items like a disabled cache. The cleared items are only
+// notified as evicted with WithEvictOnClose. A cache that isn't thread-safe has no background
+// goroutines to stop.
+func (c *lru[K, V]) Close() error {
+	if c.closed {
+		return nil
+	}
+
+	if c.closeEvict {
+		c.RemoveAll()
+	} else {
+		c.discard()
+	}
+
+	c.closed = true
+	return nil
+}
+
+// Close implements LRU.
+func (t *threadsafe[K, V]) Close() error {
+	t.closeOnce.Do(func() {
+		if t.stop != nil {
+			close(t.stop)
+			<-t.done
+		}
+
+		// In-flight refreshes need the lock to finish
+		t.refreshes.Wait()
+	})
+
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Close()
+}
+
+// Remove expired items every interval, until stopped.
+func (t *threadsafe[K, V]) sweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+
+	defer func() {
+		ticker.Stop()
+		close(t.done)
+	}()
+
+	for {
+		select {
+		case <-t.stop:
+			return
+
+		case <-ticker.C:
+			t.lock()
+			t.lru.PurgeExpired()
+			t.unlock()
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJanitor(t *testing.T) {
+	cache := NewThreadSafeWithOptions(8, WithJanitor[int, int](time.Millisecond))
+	defer cache.Close()
+
+	cache.SetWithTTL(1, 1, time.Millisecond)
+	cache.Set(2, 2)
+
+	for range 1000 {
+		if cache.Len() == 1 {
+			if !cache.Has(2) {
+				t.Fatal("expected an unexpired item to be kept")
+			}
+
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("expected the janitor to remove the expired item")
+}
+
+func TestJanitorClose(t *testing.T) {
+	cache := NewThreadSafeWithOptions(8, WithJanitor[int, int](time.Hour))
+
+	if err := cache.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Close(); err != nil {
+		t.Fatal("expected Close to be idempotent")
+	}
+}
+
+func TestClose(t *testing.T) {
+	var evicted int
+
+	cache := NewThreadSafeWithOptions(8, WithEvictOnClose[int, int](), WithEvicted(func(int, int) {
+		evicted++
+	}))
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Close()
+
+	if evicted != 2 || cache.Len() != 0 {
+		t.Fatalf("expected remaining items to be evicted, got %d evicts", evicted)
+	}
+
+	if cache.Set(3, 3) || cache.Has(3) {
+		t.Fatal("expected a closed cache to reject items")
+	}
+}
@@ -0,0 +1,67 @@
+package lru
+
+import (
+	"encoding/json"
+)
+
+var (
+	_ json.Marshaler   = (*lru[struct{}, struct{}])(nil)
+	_ json.Unmarshaler = (*lru[struct{}, struct{}])(nil)
+	_ json.Marshaler   = (*threadsafe[struct{}, struct{}])(nil)
+	_ json.Unmarshaler = (*threadsafe[struct{}, struct{}])(nil)
+)
+
+// A single cache item in its JSON form.
+type jsonItem[K comparable, V any] struct {
+	Key K `json:"key"`
+	Val V `json:"value"`
+}
+
+// Marshal all items into a JSON array, from least to most recently used.
+func (c *lru[K, V]) MarshalJSON() ([]byte, error) {
+	items := make([]jsonItem[K, V], 0, c.Len())
+
+	for k, v := range c.IterateAsc() {
+		items = append(items, jsonItem[K, V]{Key: k, Val: v})
+	}
+
+	return json.Marshal(items)
+}
+
+// Unmarshal a JSON array of items, from least to most recently used, into the cache. Any existing
+// items are discarded without notice. If there are more items than capacity, the oldest items
+// are evicted.
+func (c *lru[K, V]) UnmarshalJSON(data []byte) (err error) {
+	var items []jsonItem[K, V]
+
+	if err = json.Unmarshal(data, &items); err != nil {
+		return
+	}
+
+	c.discard()
+	c.Warm(func(yield func(K, V) bool) {
+		for i := range items {
+			if !yield(items[i].Key, items[i].Val) {
+				return
+			}
+		}
+	})
+
+	return
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t *threadsafe[K, V]) MarshalJSON() ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *threadsafe[K, V]) UnmarshalJSON(data []byte) error {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.UnmarshalJSON(data)
+}
@@ -0,0 +1,31 @@
+package lru
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func ExampleLRU_json() {
+	cache := New[string, int](3)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Get("a")
+
+	data, _ := json.Marshal(cache)
+	fmt.Println(string(data))
+
+	restored := New[string, int](3)
+	_ = json.Unmarshal(data, restored)
+
+	for k, v := range restored.IterateDesc() {
+		fmt.Println(k, v)
+	}
+
+	// Output:
+	//
+	// [{"key":"b","value":2},{"key":"c","value":3},{"key":"a","value":1}]
+	// a 1
+	// c 3
+	// b 2
+}
@@ -0,0 +1,121 @@
+package lru
+
+// Get an unexpired item and lease it, which exempts it from capacity eviction until released, as
+// with Pin. If a leased item is removed or overwritten meanwhile, its evict notice and release are
+// deferred until all leases of the key are released. The release function is safe to call more
+// than once.
+func (c *lru[K, V]) Acquire(key K) (val V, release func(), ok bool) {
+	idx, ok := c.lookup(key)
+
+	if !ok {
+		return
+	}
+
+	c.promoteOn(idx, PromoteOnGet)
+	key = c.keys[idx]
+	l := c.leases[key]
+
+	if l == nil {
+		if c.leases == nil {
+			c.leases = make(map[K]*lease[V])
+		}
+
+		l = &lease[V]{pinned: c.pinned[idx]}
+		c.leases[key] = l
+	}
+
+	if !c.pinned[idx] {
+		c.pinned[idx] = true
+		c.pins++
+	}
+
+	l.n++
+
+	var released bool
+
+	return c.copy(c.vals[idx]), func() {
+		if !released {
+			released = true
+			c.release(key, l)
+		}
+	}, true
+}
+
+type lease[V any] struct {
+	n        int  // Number of unreleased leases
+	pinned   bool // Whether the item is pinned regardless of leases
+	deferred []deferredEvict[V]
+}
+
+type deferredEvict[V any] struct {
+	val    V
+	retain bool
+	notify bool // Whether to notify the evict, or only release the value
+}
+
+func (c *lru[K, V]) release(key K, l *lease[V]) {
+	if l.n--; l.n > 0 {
+		return
+	}
+
+	delete(c.leases, key)
+
+	if !l.pinned {
+		if idx, ok := c.index(key); ok && c.pinned[idx] {
+			c.pinned[idx] = false
+			c.pins--
+		}
+	}
+
+	for _, d := range l.deferred {
+		c.retain = d.retain
+
+		if d.notify {
+			c.evict(key, d.val)
+		} else {
+			c.drop(key, d.val)
+		}
+	}
+
+	c.retain = false
+}
+
+// Defer the evict notice and release of a leased key, and return whether it was leased.
+func (c *lru[K, V]) deferEvict(key K, val V, notify bool) bool {
+	l, ok := c.leases[key]
+
+	if ok {
+		l.deferred = append(l.deferred, deferredEvict[V]{val: val, retain: c.retain, notify: notify})
+	}
+
+	return ok
+}
+
+// Unpin items that are only pinned by the leases of another cache, e.g. in a clone.
+func (c *lru[K, V]) unpinLeased(leases map[K]*lease[V]) {
+	for key, l := range leases {
+		if idx, ok := c.index(key); ok && !l.pinned && c.pinned[idx] {
+			c.pinned[idx] = false
+			c.pins--
+		}
+	}
+}
+
+// Acquire implements LRU.
+func (t *threadsafe[K, V]) Acquire(key K) (val V, release func(), ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	val, rel, ok := t.lru.Acquire(key)
+
+	if !ok {
+		return
+	}
+
+	return val, func() {
+		t.lock()
+		defer t.unlock()
+
+		rel()
+	}, true
+}
@@ -0,0 +1,93 @@
+package lru
+
+import "testing"
+
+func TestAcquire(t *testing.T) {
+	var evicted []int
+
+	cache := NewThreadSafe(2, func(k int, _ int) {
+		evicted = append(evicted, k)
+	})
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+
+	_, release, ok := cache.Acquire(1)
+	_, release2, _ := cache.Acquire(1)
+
+	if !ok {
+		t.Fatal("expected a lease")
+	}
+
+	cache.Set(3, 3)
+
+	if !cache.Has(1) || cache.Has(2) {
+		t.Fatal("expected the leased item to be exempt from eviction")
+	}
+
+	cache.Remove(1)
+
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("expected the evict notice of the leased item to be deferred, got %v", evicted)
+	}
+
+	release()
+	release()
+
+	if len(evicted) != 1 {
+		t.Fatal("expected the evict notice to wait for all leases")
+	}
+
+	release2()
+
+	if len(evicted) != 2 || evicted[1] != 1 {
+		t.Fatalf("expected the deferred evict notice, got %v", evicted)
+	}
+
+	// A released item is evictable again, unless pinned
+	_, release, _ = cache.Acquire(3)
+	cache.Pin(3)
+	release()
+	cache.Set(4, 4)
+	cache.Set(5, 5)
+
+	if !cache.Has(3) || cache.Has(4) {
+		t.Fatal("expected the pin to outlive the lease")
+	}
+}
+
+type closer struct {
+	closed *int
+}
+
+func (c *closer) Close() error {
+	*c.closed++
+	return nil
+}
+
+func TestAutoClose(t *testing.T) {
+	var closed int
+
+	cache := NewThreadSafeWithOptions(2, WithAutoClose[int, closer]())
+
+	cache.Set(1, closer{&closed})
+	cache.Set(2, closer{&closed})
+	cache.Set(3, closer{&closed})
+
+	if closed != 1 {
+		t.Fatalf("expected the evicted value to be closed, got %d closes", closed)
+	}
+
+	_, release, _ := cache.Acquire(2)
+	cache.Reset()
+
+	if closed != 2 {
+		t.Fatalf("expected reset values to be closed, except leased ones, got %d closes", closed)
+	}
+
+	release()
+
+	if closed != 3 {
+		t.Fatalf("expected the leased value to be closed once released, got %d closes", closed)
+	}
+}
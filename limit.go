@@ -0,0 +1,58 @@
+package lru
+
+import "time"
+
+// Limit the setter calls of GetOrSet and its variants to rate per second, with bursts of up to
+// burst calls, e.g. to protect an origin during a cold start or mass invalidation. A refused call
+// returns the expired value of the key if it's yet to be removed, and ErrLoaderThrottled
+// otherwise. Background refreshes are skipped while throttled.
+func WithLoaderLimit[K comparable, V any](rate float64, burst int) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.limiter = limiter{
+			rate:   rate / float64(time.Second),
+			burst:  float64(max(burst, 1)),
+			tokens: float64(max(burst, 1)),
+		}
+	}
+}
+
+// Token bucket of setter calls.
+type limiter struct {
+	rate   float64 // Tokens per nanosecond, or zero if unlimited
+	burst  float64
+	tokens float64
+	last   int64 // Unix nanoseconds of the last refill
+}
+
+// Take a token, if any.
+func (l *limiter) allow(now int64) bool {
+	if l.rate <= 0 {
+		return true
+	}
+
+	if l.last != 0 {
+		l.tokens = min(l.tokens+float64(now-l.last)*l.rate, l.burst)
+	}
+
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+
+	l.tokens--
+	return true
+}
+
+// Whether a setter call is refused, and if so the stale value or error to return instead.
+func (c *lru[K, V]) throttled(key K) (val V, refused bool, err error) {
+	if c.limiter.allow(c.now()) {
+		return
+	}
+
+	if val, ok := c.staleValue(key); ok {
+		return val, true, nil
+	}
+
+	return val, true, ErrLoaderThrottled
+}
@@ -0,0 +1,58 @@
+package lru
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLoaderLimit(t *testing.T) {
+	var calls int
+
+	clock := newFakeClock()
+	cache := NewThreadSafeWithOptions(8, WithClock[int, int](clock), WithLoaderLimit[int, int](1, 2))
+	setter := func(key int) (int, error) {
+		calls++
+		return key * 10, nil
+	}
+
+	cache.GetOrSetTTL(1, time.Millisecond, setter)
+	cache.GetOrSet(2, setter)
+
+	if _, err := cache.GetOrSet(3, setter); err != ErrLoaderThrottled || calls != 2 {
+		t.Fatalf("expected the burst to be exhausted, got %v after %d calls", err, calls)
+	}
+
+	clock.Advance(time.Millisecond)
+
+	if v, err := cache.GetOrSet(1, setter); err != nil || v != 10 || calls != 2 {
+		t.Fatalf("expected the stale value, got %d and %v", v, err)
+	}
+
+	clock.Advance(time.Second)
+
+	if v, err := cache.GetOrSet(3, setter); err != nil || v != 30 || calls != 3 {
+		t.Fatalf("expected a refilled token, got %d and %v", v, err)
+	}
+}
+
+func TestLoaderLimitGetter(t *testing.T) {
+	var calls int
+
+	cache := NewThreadSafeWithOptions(8, WithClock[int, int](newFakeClock()), WithLoaderLimit[int, int](1, 1))
+	getter := NewGetter(cache, GetterFunc[int, int](func(_ context.Context, key int) (int, error) {
+		calls++
+		return key * 10, nil
+	}))
+
+	// Exhaust the burst, which mustn't keep an already loaded value from being cached
+	cache.GetOrSet(2, func(key int) (int, error) { return key * 10, nil })
+
+	if v, err := getter.Get(context.Background(), 1); err != nil || v != 10 {
+		t.Fatalf("expected a loaded value, got %d and %v", v, err)
+	}
+
+	if v, ok := cache.Get(1); !ok || v != 10 || calls != 1 {
+		t.Fatal("expected the loaded value to be cached without a token of its own")
+	}
+}
@@ -0,0 +1,113 @@
+package lru
+
+import (
+	"sync"
+	"time"
+)
+
+// Local is a facade in front of a shared thread-safe cache, that serves hits from small caches
+// without locks. Each goroutine borrows a local cache through a sync.Pool, so that local caches
+// are mostly per processor. Every interval, a local cache promotes its hits in the shared cache
+// and is cleared, so values of other goroutines' writes may be stale for up to interval.
+type Local[K comparable, V any] struct {
+	shared   LRU[K, V]
+	size     int
+	interval time.Duration
+	pool     sync.Pool
+}
+
+type localCache[K comparable, V any] struct {
+	cache LRU[K, V]
+	hot   map[K]struct{} // Keys hit since the last consolidation
+	since time.Time
+}
+
+// Create a facade of local caches of size items in front of a shared cache.
+func NewLocal[K comparable, V any](shared LRU[K, V], size int, interval time.Duration) *Local[K, V] {
+	return &Local[K, V]{
+		shared:   shared,
+		size:     size,
+		interval: interval,
+	}
+}
+
+// Get an item from the local cache, or from the shared cache on a local miss.
+func (l *Local[K, V]) Get(key K) (val V, ok bool) {
+	c := l.borrow()
+	defer l.pool.Put(c)
+
+	if val, ok = c.cache.Get(key); ok {
+		c.hot[key] = struct{}{}
+		return
+	}
+
+	if val, ok = l.shared.Get(key); ok {
+		c.cache.Replace(key, val)
+	}
+
+	return
+}
+
+// Get an item from the local or shared cache, or set it in the shared cache with the setter.
+func (l *Local[K, V]) GetOrSet(key K, setter func(K) (V, error)) (val V, err error) {
+	c := l.borrow()
+	defer l.pool.Put(c)
+
+	var ok bool
+
+	if val, ok = c.cache.Get(key); ok {
+		c.hot[key] = struct{}{}
+		return
+	}
+
+	if val, err = l.shared.GetOrSet(key, setter); err == nil {
+		c.cache.Replace(key, val)
+	}
+
+	return
+}
+
+// Add or overwrite an item in the shared cache.
+func (l *Local[K, V]) Set(key K, val V) {
+	c := l.borrow()
+	defer l.pool.Put(c)
+
+	l.shared.Replace(key, val)
+	c.cache.Replace(key, val)
+}
+
+// Remove an item from the shared cache. Other local caches might still hold it for up to interval.
+func (l *Local[K, V]) Remove(key K) (existed bool) {
+	c := l.borrow()
+	defer l.pool.Put(c)
+
+	c.cache.Remove(key)
+	delete(c.hot, key)
+
+	return l.shared.Remove(key)
+}
+
+// Borrow a local cache, and consolidate it if it's due.
+func (l *Local[K, V]) borrow() *localCache[K, V] {
+	c, _ := l.pool.Get().(*localCache[K, V])
+
+	if c == nil {
+		return &localCache[K, V]{
+			cache: New[K, V](l.size),
+			hot:   make(map[K]struct{}),
+			since: time.Now(),
+		}
+	}
+
+	if now := time.Now(); now.Sub(c.since) >= l.interval {
+		for key := range c.hot {
+			l.shared.Touch(key)
+		}
+
+		clear(c.hot)
+		c.cache.Reset()
+		c.since = now
+	}
+
+	return c
+}
@@ -0,0 +1,54 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLocal(t *testing.T) {
+	shared := NewThreadSafe[int, int](8)
+	local := NewLocal(shared, 8, time.Hour)
+
+	shared.Set(1, 1)
+	local.Set(2, 2)
+
+	if v, ok := local.Get(1); !ok || v != 1 {
+		t.Fatalf("expected a hit in the shared cache, got %d", v)
+	}
+
+	if v, ok := shared.Get(2); !ok || v != 2 {
+		t.Fatalf("expected a local set to reach the shared cache, got %d", v)
+	}
+
+	if !local.Remove(1) || shared.Has(1) {
+		t.Fatal("expected a local remove to reach the shared cache")
+	}
+}
+
+func TestLocalConcurrent(t *testing.T) {
+	shared := NewThreadSafe[int, int](64)
+	local := NewLocal(shared, 8, time.Millisecond)
+
+	var wg sync.WaitGroup
+
+	for g := range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range 1000 {
+				if _, err := local.GetOrSet(i%16, func(k int) (int, error) { return k, nil }); err != nil {
+					t.Error(err)
+				}
+
+				if i%100 == g {
+					local.Remove(i % 16)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -3,159 +3,735 @@ package lru
 import (
 	"iter"
 	"math"
+	"reflect"
+	"sync/atomic"
+	"time"
 )
 
 type LRU[K comparable, V any] interface {
 	Len() int
 	Cap() int
-	Resize(capacity int)
+
+	// Number of items that can be added without evicting, or math.MaxInt if unbounded.
+	Available() int
+
+	// Evict items until at least n items can be added without evicting, and notify each evict.
+	// Returns false, without evicting anything, if pinned items or the capacity leave no room for
+	// n items. Use Batch or ReserveAndSet to keep other goroutines from filling the room.
+	Reserve(n int) (ok bool)
+
+	// Reserve room for all items of a sequence, and add them as with Set, as one with respect to
+	// other goroutines. Returns false, without adding anything, if the room can't be reserved.
+	ReserveAndSet(seq iter.Seq2[K, V]) (ok bool)
+
+	// Change the capacity, and return any items evicted right away.
+	Resize(capacity int, opts ...ResizeOption) (evicted []EntryInfo[K, V])
 	Has(key K) (ok bool)
-	Get(key K) (val V, ok bool)
-	GetOrSet(key K, setter func(K) (V, error)) (val V, err error)
+
+	// Mark an item as most recently used, without reading its value.
+	Touch(key K) (ok bool)
+	Get(key K, opts ...CallOption) (val V, ok bool)
+
+	// Same as Get, but returns ErrNotFound, ErrExpired or ErrClosed instead of false.
+	GetErr(key K) (val V, err error)
+	GetOrSet(key K, setter func(K) (V, error), opts ...CallOption) (val V, err error)
+
+	// Same as GetOrSet, but the set value expires after ttl. A ttl of zero or less never expires.
+	GetOrSetTTL(key K, ttl time.Duration, setter func(K) (V, error), opts ...CallOption) (val V, err error)
+
+	// Same as GetOrSet, but the setter decides when the set value expires. A ttl of zero or less
+	// never expires.
+	GetOrSetExpiring(key K, setter func(K) (V, time.Duration, error), opts ...CallOption) (val V, err error)
+
+	// Get an existing item, or set the provided value. Like sync.Map's LoadOrStore, loaded
+	// reports whether the value was found.
+	GetOrSetValue(key K, val V) (actual V, loaded bool)
+
+	// Add an item only if the key doesn't exist. Returns false, and leaves any existing item
+	// untouched, if it does.
 	Set(key K, val V) (ok bool)
+
+	// Add or overwrite an item and mark it as most recently used. An overwritten value is
+	// notified as evicted.
 	Replace(key K, val V) (existed bool)
+
+	// Same as Set, but returns the item evicted to make room, if any.
+	SetEvict(key K, val V) (evictedKey K, evictedVal V, evicted bool)
+
+	// Same as Replace, but returns the item evicted to make room, if any.
+	ReplaceEvict(key K, val V) (evictedKey K, evictedVal V, evicted bool)
+
+	// Same as Upsert, but reports whether an item was evicted to make room.
+	Add(key K, val V) (evicted bool)
+
+	// Add an item only if the key doesn't exist, without affecting the recency of an existing
+	// item. Reports whether the key existed, and whether an item was evicted to make room.
+	ContainsOrAdd(key K, val V) (existed, evicted bool)
+
+	// Same as ContainsOrAdd, but also returns the value of an existing item.
+	PeekOrAdd(key K, val V) (prev V, existed, evicted bool)
+
+	// Add or overwrite an item and mark it as most recently used. Unlike Replace, an overwritten
+	// value is not notified as evicted, as the item is updated rather than evicted, but it's still
+	// released.
+	Upsert(key K, val V) (updated bool)
+
+	// Same as Replace, but the item expires after ttl. A ttl of zero or less never expires.
+	SetWithTTL(key K, val V, ttl time.Duration) (existed bool)
+
+	// Change when an unexpired item expires, without affecting recency. A zero time never expires.
+	ExpireAt(key K, t time.Time) (ok bool)
+
+	// Remaining lifetime of an unexpired item, or zero if it never expires. Doesn't affect recency.
+	TTL(key K) (ttl time.Duration, ok bool)
+
+	// Remove all expired items, and notify each evict. Returns the number of removed items.
+	PurgeExpired() (removed int)
+
+	// Iterate all expired items that are yet to be removed, in no particular order.
+	IterateExpired() iter.Seq2[K, V]
 	Remove(key K) (existed bool)
+
+	// Add or overwrite an item and mark it as most recently used, and return the previous value
+	// of an unexpired item. As with Upsert, the previous value is not notified as evicted, and it's
+	// only released if a copy is returned.
+	Swap(key K, val V) (old V, existed bool)
+
+	// Remove an unexpired item and return its value. The item is notified as evicted, as with
+	// Remove.
+	GetAndRemove(key K) (val V, ok bool)
+
+	// Remove every item matching the predicate, and notify each evict. Returns the number of
+	// removed items. The predicate must not call the cache.
+	RemoveFunc(pred func(K, V) bool) (removed int)
+
+	// Iterate all items. A cache that isn't thread-safe panics if changed during iteration, unless
+	// the iteration is stopped right after. Thread-safe caches iterate a snapshot instead.
 	Iterate() iter.Seq2[K, V]
 	IterateAsc() iter.Seq2[K, V]
 	IterateDesc() iter.Seq2[K, V]
 
+	// Iterate at most the n most recently used items, in descending order.
+	IterateN(n int) iter.Seq2[K, V]
+
+	// Iterate the items matching the predicate, in descending order. The predicate must not call
+	// the cache, as a thread-safe cache holds its lock while matching.
+	IterateWhere(pred func(K, V) bool) iter.Seq2[K, V]
+
+	// Add or overwrite all items of a sequence, from least to most recently used, as with Replace.
+	// Any function of the iter.Seq2 signature can be used as a loader. The sequence must not call
+	// the cache, as a thread-safe cache holds its lock during the whole sequence.
+	Warm(seq iter.Seq2[K, V])
+
+	// Copy of the cache, including recency, expiry, pins, aliases and settings, but not stats.
+	// The copy is thread-safe if the cache is.
+	Clone() LRU[K, V]
+
+	// Add or overwrite all items in another cache, from least to most recently used and with
+	// their remaining ttl, as with SetWithTTL.
+	CopyTo(dst LRU[K, V])
+
+	// Fold the items of another cache into this one, interleaving both recency orders by the
+	// relative position of each item. An item in both caches gets the later of its two positions,
+	// and the value returned by onConflict, or the other cache's value if onConflict is nil. If the
+	// items exceed the capacity, the least recently used are evicted or rejected.
+	Merge(other LRU[K, V], onConflict func(key K, ours, theirs V) V)
+
+	// Same as Get, but also returns the version of the item, for use with CompareAndSwap.
+	GetVersioned(key K) (val V, version uint64, ok bool)
+
+	// Overwrite an unexpired item only if its version is unchanged, and mark it as most recently
+	// used. A version of zero adds the item only if it doesn't exist. The item keeps its expiry,
+	// and the overwritten value is released but not notified as evicted.
+	CompareAndSwap(key K, version uint64, val V) (swapped bool)
+
+	// Compute a new value from the current one, if any, or remove the item if fn returns delete.
+	// The item is marked as most recently used and keeps its expiry, and a replaced value is
+	// released but not notified as evicted. Returns the resulting value, and whether the item exists. fn must not
+	// call the cache.
+	Compute(key K, fn func(old V, exists bool) (val V, delete bool)) (val V, ok bool)
+
+	// Run fn with a view of the cache, whose changes are applied as one with respect to other
+	// goroutines. fn must only use the view, not the cache.
+	Batch(fn func(tx Tx[K, V]))
+
+	// Detached copy of the cache, which isn't thread-safe and has no callbacks.
+	Snapshot() LRU[K, V]
+
+	// The least recently used item, which is next in line for eviction. Doesn't affect recency.
+	Oldest() (key K, val V, ok bool)
+
+	// The most recently used item. Doesn't affect recency.
+	Newest() (key K, val V, ok bool)
+
+	// Position of an unexpired item in descending order, where 0 is the most recently used.
+	// Doesn't affect recency.
+	Rank(key K) (pos int, ok bool)
+
+	// Remove the least recently used item, and notify its evict.
+	RemoveOldest() (key K, val V, ok bool)
+
+	// Remove the most recently used item, and notify its evict.
+	RemoveNewest() (key K, val V, ok bool)
+
+	// Register an alias for a primary key, so that the item can be addressed by both. Aliases are
+	// removed together with their item. Returns false if the primary key doesn't exist, or if the
+	// alias is already a primary key.
+	Alias(alias K, primary K) (ok bool)
+
+	// Remove an alias, but keep its item.
+	Unalias(alias K) (existed bool)
+
+	// Same as Set, but also tags the new item, so that it can be removed together with other
+	// items of a tag by InvalidateTag. Tags are removed together with their item.
+	SetTagged(key K, val V, tags ...string) (ok bool)
+
+	// Remove every item of a tag, and notify each evict. Returns the number of removed items.
+	InvalidateTag(tag string) (removed int)
+
+	// Exempt an item from capacity eviction. Pinned items still count toward Len, and can still
+	// be removed explicitly. If all items are pinned, new items are rejected.
+	Pin(key K) (ok bool)
+
+	// Make a pinned item evictable again.
+	Unpin(key K) (ok bool)
+
+	// Get an unexpired item and lease it, which exempts it from capacity eviction until released,
+	// as with Pin. If a leased item is removed or overwritten meanwhile, its evict notice and
+	// release are deferred until all leases of the key are released. The release function is safe
+	// to call more than once.
+	Acquire(key K) (val V, release func(), ok bool)
+
+	// Whether an item might exist, as checked by the filter of WithFilter. False means that it
+	// definitely doesn't exist. Without WithFilter, this equals Has.
+	MightContain(key K) bool
+
+	// Metadata of an item, without affecting recency.
+	Entry(key K) (info EntryInfo[K, V], ok bool)
+
+	// Iterate the metadata of all items in ascending order.
+	IterateEntries() iter.Seq[EntryInfo[K, V]]
+
+	// Cumulative hit, miss and eviction counters.
+	Stats() Stats
+
+	// Estimate the capacity needed to reach a target hit ratio (0-1), based on hits, misses and
+	// misses of recently evicted keys. Requires WithGhosts to look beyond the current capacity.
+	Recommendation(target float64) Recommendation
+
+	// The n unexpired keys with the most hits, ordered by hits and then by size.
+	TopKeys(n int) []KeyStat[K]
+
+	// Snapshot of all keys, from least to most recently used.
+	Keys() []K
+
+	// Snapshot of all values, from least to most recently used.
+	Values() []V
+
 	// Clear cache and notify each evict. To clear cache without notice, use Reset.
 	RemoveAll()
 
-	// Clear cache without notice. To clear cache and notify each evict, use RemoveAll.
+	// Clear cache without notice, except for releasing values with WithReleaser or WithAutoClose.
+	// To clear cache and notify each evict, use RemoveAll.
 	Reset()
+
+	// Estimate the memory held by the cache in bytes, i.e. its allocated storage plus the size of
+	// each item reported by WithSizer.
+	SizeBytes() int64
+
+	// Check the internal invariants of the cache, and return an ErrCorrupt error describing the
+	// first violation, if any. Takes time linear in the number of items.
+	Validate() error
+
+	// Stop any background goroutines and clear the cache, which then rejects all items like a
+	// disabled cache. The cleared items are only notified as evicted with WithEvictOnClose.
+	Close() error
 }
 
 var _ LRU[struct{}, struct{}] = (*lru[struct{}, struct{}])(nil)
 
+// ExpiryProvider is implemented by values that know their own freshness, e.g. HTTP responses or
+// signed tokens. A value returned by a GetOrSet setter expires at its ExpiresAt time, unless the
+// setter returns an explicit ttl. A zero time never expires.
+type ExpiryProvider interface {
+	ExpiresAt() time.Time
+}
+
+// Clock provides the current time for expiry, e.g. a fake clock in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Maximum number of items evicted at once when shrinking the capacity.
+const resizeStep = 64
+
 // LRU cache. Not thread-safe.
 type lru[K comparable, V any] struct {
-	keys    []K
-	vals    []V
-	lastUse []uint64
-	tick    uint64
-	evicted func(K, V)
+	keys       []K
+	vals       []V
+	older      []int   // Index of the next less recently used item, or -1 if oldest
+	newer      []int   // Index of the next more recently used item, or -1 if newest
+	expires    []int64 // Unix nanoseconds, or zero if never
+	pinned     []bool
+	meta       []entryMeta
+	pins       int
+	aliases    map[K]K // Alias -> primary key
+	oldest     int     // Index of the least recently used item, or -1 if empty
+	newest     int     // Index of the most recently used item, or -1 if empty
+	gen        uint64  // Bumped on every change of the recency order
+	version    uint64  // Last version of any item, never reset so that versions aren't reused
+	capacity   int
+	unbounded  bool
+	entryTimes bool
+	closed     bool
+	closeEvict bool
+	clock      Clock
+	evicted    func(K, V)
+	rejected   func(K, V)
+	inserted   func(K, V)
+	changed    func(K)
+	copier     func(V) V
+	sizer      func(K, V) int
+	onHit      func(K, V)
+	metrics    MetricsSink
+	events     func(Event[K])
+	stats      counters
+	ghosts     ghosts[K]
+	negatives  negatives[K]
+	filter     filter[K]
+	trace      tracer[K]
+	capture    *evictedItem[K, V] // Receives the first eviction due to capacity, if set
+	resizing   ResizeOption       // Options of the last Resize, until within capacity
+	cost       func(K, V) float64
+	inflation  float64 // Priority of the last item evicted by cost
+	writeTTL   int64   // Nanoseconds, or zero if never
+	idle       int64   // Nanoseconds, or zero if never
+	releaser   func(V)
+	retain     bool // Whether the value being removed is returned, and mustn't be released
+	transform  func(K) K
+	limiter    limiter
+	selector   func(iter.Seq2[K, V]) K
+	candidates int                       // Number of candidates of the selector
+	tags       map[string]map[K]struct{} // Tag -> keys
+	tagsOf     map[K][]string            // Key -> tags
+	leases     map[K]*lease[V]
+	strict     bool // Whether Reset panics with an evict callback
+	decay      decay
+	samples    int       // Number of items sampled per eviction, or zero if exact
+	stamp      uint64    // Last use of any item, when sampling
+	noPromote  PromoteOn // Operations that don't promote existing items
+	mru        bool      // Whether to evict the most recently used item
+	s3         *s3fifo[K]
+
+	// Only used by the thread-safe cache
+	promoteEvery  uint64
+	promoteBuffer int
+	refreshAhead  int64 // Nanoseconds before expiry
+	janitor       time.Duration
 }
 
+// Create a cache. A capacity of zero or less creates a disabled cache that rejects all items,
+// unless WithUnbounded is used. Not thread-safe.
 func New[K comparable, V any](capacity int, evicted ...func(key K, val V)) LRU[K, V] {
-	c := &lru[K, V]{
-		keys:    make([]K, 0, capacity),
-		vals:    make([]V, 0, capacity),
-		lastUse: make([]uint64, 0, capacity),
-	}
+	return NewWithOptions(capacity, evictedOption(evicted)...)
+}
 
-	if len(evicted) > 0 {
-		c.evicted = evicted[0]
+func (c *lru[K, V]) init(capacity int, opts []Option[K, V]) {
+	size := max(capacity, 0)
+
+	c.keys = make([]K, 0, size)
+	c.vals = make([]V, 0, size)
+	c.older = make([]int, 0, size)
+	c.newer = make([]int, 0, size)
+	c.expires = make([]int64, 0, size)
+	c.pinned = make([]bool, 0, size)
+	c.meta = make([]entryMeta, 0, size)
+	c.oldest = -1
+	c.newest = -1
+	c.capacity = capacity
+	c.clock = systemClock{}
+
+	for _, opt := range opts {
+		opt(c)
 	}
-
-	return c
 }
 
 func (c *lru[K, V]) Len() int {
 	return len(c.keys)
 }
 
+// Configured capacity. Zero or less is either unbounded or disabled, depending on WithUnbounded.
 func (c *lru[K, V]) Cap() int {
-	return len(c.keys)
+	return c.capacity
 }
 
-func (c *lru[K, V]) Resize(capacity int) {
-	if cap(c.keys) == capacity {
-		return
+// Number of items that can be added without evicting, or math.MaxInt if unbounded. Always zero
+// when closed.
+func (c *lru[K, V]) Available() int {
+	if limit := c.limit(); limit < math.MaxInt {
+		return max(limit-len(c.keys), 0)
 	}
 
-	for capacity < c.Len() {
-		c.removeOldest()
+	return math.MaxInt
+}
+
+// Change the capacity. Growing is instant. Shrinking evicts at most resizeStep items at once, and
+// the rest in steps on subsequent inserts, so Len may exceed the capacity for a while, unless
+// ResizeNow is used. Items evicted right away are also returned, in order of eviction.
+func (c *lru[K, V]) Resize(capacity int, opts ...ResizeOption) (evicted []EntryInfo[K, V]) {
+	if c.capacity == capacity {
+		return
 	}
 
-	keys := append(make([]K, 0, capacity), c.keys...)
-	vals := append(make([]V, 0, capacity), c.vals...)
-	lastUse := append(make([]uint64, 0, capacity), c.lastUse...)
+	c.capacity = capacity
+	c.resizing = resizeFlags(opts)
 
-	c.Reset()
+	if c.resizing&resizeLazy == 0 {
+		c.shrink(&evicted)
+	}
 
-	c.keys = keys
-	c.vals = vals
-	c.lastUse = lastUse
+	return
 }
 
-// Clear cache without notice. To clear cache and notify each evict, use RemoveAll.
+// Clear cache without notice, except for releasing values with WithReleaser or WithAutoClose. To
+// clear cache and notify each evict, use RemoveAll.
 func (c *lru[K, V]) Reset() {
+	if c.strict && (c.evicted != nil || c.releaser != nil) {
+		panic("lru: Reset of a cache with an evict callback or releaser, use RemoveAll")
+	}
+
+	c.discard()
+}
+
+// Clear cache and release its values, without notice.
+func (c *lru[K, V]) discard() {
+	if c.releaser != nil {
+		for i := range c.keys {
+			c.drop(c.keys[i], c.vals[i])
+		}
+	}
+
+	c.reset()
+}
+
+func (c *lru[K, V]) reset() {
 	clear(c.keys)
 	clear(c.vals)
-	clear(c.lastUse)
+	clear(c.older)
+	clear(c.newer)
+	clear(c.expires)
+	clear(c.pinned)
+	clear(c.meta)
 
 	c.keys = c.keys[:0]
 	c.vals = c.vals[:0]
-	c.lastUse = c.lastUse[:0]
-	c.tick = 0
+	c.older = c.older[:0]
+	c.newer = c.newer[:0]
+	c.expires = c.expires[:0]
+	c.pinned = c.pinned[:0]
+	c.meta = c.meta[:0]
+	c.pins = 0
+	c.aliases = nil
+	c.tags = nil
+	c.tagsOf = nil
+	c.negatives.errs = nil
+	c.oldest = -1
+	c.newest = -1
+	c.gen++
+
+	if c.s3 != nil {
+		c.s3.reset()
+	}
+
+	if c.filter.bits != nil {
+		c.filter.reset(c.filter.n)
+	}
 }
 
 func (c *lru[K, V]) Has(key K) (ok bool) {
-	for i := range c.keys {
-		if c.keys[i] == key {
-			return true
-		}
+	idx, ok := c.index(key)
+	return ok && c.alive(idx)
+}
+
+// Mark an item as most recently used, without reading its value.
+func (c *lru[K, V]) Touch(key K) (ok bool) {
+	idx, ok := c.index(key)
+
+	if ok = ok && c.alive(idx); ok {
+		c.promote(idx)
 	}
 
 	return
 }
 
-func (c *lru[K, V]) Get(key K) (val V, ok bool) {
-	for i := range c.keys {
-		if c.keys[i] == key {
-			c.lastUse[i] = c.nextTick()
-			return c.vals[i], true
-		}
+func (c *lru[K, V]) Get(key K, opts ...CallOption) (val V, ok bool) {
+	if callFlags(opts) != 0 {
+		return
+	}
+
+	idx, ok := c.lookup(key)
+
+	if ok {
+		c.promoteOn(idx, PromoteOnGet)
+		val = c.copy(c.vals[idx])
+	}
+
+	return
+}
+
+// Find an unexpired item and record the hit or miss, without affecting recency. Safe for
+// concurrent reads.
+func (c *lru[K, V]) lookup(key K) (idx int, ok bool) {
+	idx, ok = c.index(key)
+
+	if !ok || !c.alive(idx) {
+		c.miss(key)
+		return idx, false
+	}
+
+	c.hit(key)
+	c.accessed(idx)
+
+	if c.onHit != nil {
+		c.onHit(key, c.vals[idx])
 	}
 
 	return
 }
 
-func (c *lru[K, V]) GetOrSet(key K, setter func(K) (V, error)) (val V, err error) {
-	var ok bool
+func (c *lru[K, V]) GetOrSet(key K, setter func(K) (V, error), opts ...CallOption) (val V, err error) {
+	return c.GetOrSetTTL(key, 0, setter, opts...)
+}
 
-	if val, ok = c.Get(key); ok {
+func (c *lru[K, V]) GetOrSetTTL(key K, ttl time.Duration, setter func(K) (V, error), opts ...CallOption) (val V, err error) {
+	return c.getOrSet(key, callFlags(opts), func(key K) (val V, _ time.Duration, err error) {
+		val, err = setter(key)
+		return val, ttl, err
+	})
+}
+
+func (c *lru[K, V]) GetOrSetExpiring(key K, setter func(K) (V, time.Duration, error), opts ...CallOption) (val V, err error) {
+	return c.getOrSet(key, callFlags(opts), setter)
+}
+
+func (c *lru[K, V]) getOrSet(key K, flags CallOption, setter func(K) (V, time.Duration, error)) (val V, err error) {
+	var (
+		ok  bool
+		ttl time.Duration
+	)
+
+	if flags == 0 {
+		if val, ok = c.Get(key); ok {
+			return
+		}
+
+		if err = c.negativeErr(key); err != nil {
+			return
+		}
+	}
+
+	if val, ok, err = c.throttled(key); ok {
 		return
 	}
 
-	if val, err = setter(key); err == nil {
-		c.append(key, val)
+	if val, ttl, err = setter(key); flags&bypass == 0 {
+		c.storeLoaded(key, val, ttl, err)
 	}
 
 	return
 }
 
-func (c *lru[K, V]) Set(key K, val V) (ok bool) {
-	if c.Has(key) {
+// Store a loaded value, or remember the error of its loader.
+func (c *lru[K, V]) storeLoaded(key K, val V, ttl time.Duration, err error) {
+	if err == nil {
+		c.put(key, val, c.loadedExpiry(val, ttl))
+	} else {
+		c.rememberErr(key, err)
+	}
+}
+
+func (c *lru[K, V]) GetOrSetValue(key K, val V) (actual V, loaded bool) {
+	if actual, loaded = c.Get(key); loaded {
 		return
 	}
 
-	c.append(key, val)
+	c.put(key, val, 0)
+	return val, false
+}
 
-	return true
+func (c *lru[K, V]) Set(key K, val V) (ok bool) {
+	idx, found := c.index(key)
+
+	if !found {
+		ok = c.append(key, val, 0)
+	} else if !c.alive(idx) {
+		c.overwrite(idx, val, 0)
+		ok = true
+	}
+
+	if ok {
+		c.change(key)
+	}
+
+	return
+}
+
+// Same as Upsert, but reports whether an item was evicted to make room.
+func (c *lru[K, V]) Add(key K, val V) (evicted bool) {
+	before := c.stats.evictions.Load()
+	c.Upsert(key, val)
+	return c.stats.evictions.Load() != before
+}
+
+// Add an item only if the key doesn't exist, without affecting the recency of an existing item.
+// Reports whether the key existed, and whether an item was evicted to make room.
+func (c *lru[K, V]) ContainsOrAdd(key K, val V) (existed, evicted bool) {
+	_, existed, evicted = c.PeekOrAdd(key, val)
+	return
+}
+
+// Same as ContainsOrAdd, but also returns the value of an existing item.
+func (c *lru[K, V]) PeekOrAdd(key K, val V) (prev V, existed, evicted bool) {
+	if idx, found := c.index(key); found && c.alive(idx) {
+		return c.copy(c.vals[idx]), true, false
+	}
+
+	before := c.stats.evictions.Load()
+	c.Set(key, val)
+	return prev, false, c.stats.evictions.Load() != before
+}
+
+// Same as Set, but returns the item evicted to make room, if any.
+func (c *lru[K, V]) SetEvict(key K, val V) (evictedKey K, evictedVal V, evicted bool) {
+	var item evictedItem[K, V]
+
+	c.capture = &item
+	c.Set(key, val)
+	c.capture = nil
+
+	return item.key, item.val, item.ok
+}
+
+// Same as Replace, but returns the item evicted to make room, if any.
+func (c *lru[K, V]) ReplaceEvict(key K, val V) (evictedKey K, evictedVal V, evicted bool) {
+	var item evictedItem[K, V]
+
+	c.capture = &item
+	c.Replace(key, val)
+	c.capture = nil
+
+	return item.key, item.val, item.ok
+}
+
+type evictedItem[K comparable, V any] struct {
+	key K
+	val V
+	ok  bool
 }
 
 func (c *lru[K, V]) Replace(key K, val V) (existed bool) {
-	for i := range c.keys {
-		if c.keys[i] == key {
-			c.vals[i], val = val, c.vals[i]
-			c.lastUse[i] = c.nextTick()
-			c.evict(key, val)
-			return true
-		}
+	existed = c.put(key, val, 0)
+	c.change(key)
+	return
+}
+
+func (c *lru[K, V]) Upsert(key K, val V) (updated bool) {
+	idx, found := c.index(key)
+
+	if !found {
+		c.append(key, val, 0)
+	} else if !c.alive(idx) {
+		c.overwrite(idx, val, 0)
+	} else {
+		c.replaceValue(idx, c.copy(val))
+		c.promoteOn(idx, PromoteOnSet)
+		c.expires[idx] = c.expiry(0)
+		c.meta[idx].version = c.nextVersion()
+		c.weigh(idx)
+		updated = true
+	}
+
+	c.change(key)
+	return
+}
+
+// Add or overwrite an item and mark it as most recently used, and return the previous value of
+// an unexpired item. As with Upsert, the previous value is not notified as evicted, and it's only
+// released if the returned value is a copy of it.
+func (c *lru[K, V]) Swap(key K, val V) (old V, existed bool) {
+	if idx, found := c.index(key); found && c.alive(idx) {
+		old, existed = c.copy(c.vals[idx]), true
 	}
 
-	c.append(key, val)
+	c.retain = existed && c.copier == nil
+	c.Upsert(key, val)
+	c.retain = false
+	return
+}
+
+// Remove an unexpired item and return its value. The item is notified as evicted, as with Remove.
+func (c *lru[K, V]) GetAndRemove(key K) (val V, ok bool) {
+	idx, found := c.index(key)
+
+	if !found {
+		return
+	}
+
+	if ok = c.alive(idx); ok {
+		val = c.copy(c.vals[idx])
+	}
+
+	c.removeReturned(idx)
+
+	if ok {
+		c.change(key)
+	}
 
 	return
 }
 
 func (c *lru[K, V]) Remove(key K) (existed bool) {
-	for i := range c.keys {
-		if c.keys[i] == key {
+	if existed = c.removeKey(key); existed {
+		c.change(key)
+	}
+
+	return
+}
+
+// Remove an item without publishing the change, and return whether an unexpired item existed.
+func (c *lru[K, V]) removeKey(key K) (existed bool) {
+	idx, existed := c.index(key)
+
+	if existed {
+		existed = c.alive(idx)
+		c.remove(idx)
+	}
+
+	return
+}
+
+// Add or overwrite all items of a sequence, from least to most recently used, as with Replace.
+func (c *lru[K, V]) Warm(seq iter.Seq2[K, V]) {
+	for k, v := range seq {
+		c.put(k, v, 0)
+	}
+}
+
+// Remove every item matching the predicate, and notify each evict. Returns the number of
+// removed items. The predicate must not call the cache.
+func (c *lru[K, V]) RemoveFunc(pred func(K, V) bool) (removed int) {
+	// Walk backwards, as removal swaps the last item into the removed slot.
+	for i := len(c.keys) - 1; i >= 0; i-- {
+		if key := c.keys[i]; c.alive(i) && pred(key, c.vals[i]) {
 			c.remove(i)
-			return true
+			c.change(key)
+			removed++
 		}
 	}
 
@@ -168,16 +744,24 @@ func (c *lru[K, V]) RemoveAll() {
 		c.evict(c.keys[i], c.vals[i])
 	}
 
-	c.Reset()
+	c.reset()
 }
 
 // Iterate all items in no particular order.
 func (c *lru[K, V]) Iterate() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
+		gen := c.gen
+
 		for i := range c.keys {
-			if !yield(c.keys[i], c.vals[i]) {
+			if !c.alive(i) {
+				continue
+			}
+
+			if !yield(c.keys[i], c.copy(c.vals[i])) {
 				return
 			}
+
+			c.unchanged(gen)
 		}
 	}
 }
@@ -185,16 +769,29 @@ func (c *lru[K, V]) Iterate() iter.Seq2[K, V] {
 // Iterate all items in ascending order.
 func (c *lru[K, V]) IterateAsc() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		tick := c.oldestTick()
+		for idx := range c.ascending() {
+			if !yield(c.keys[idx], c.copy(c.vals[idx])) {
+				return
+			}
+		}
+	}
+}
+
+// Iterate the indices of all unexpired items in ascending order.
+func (c *lru[K, V]) ascending() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		gen := c.gen
 
-		for range c.keys {
-			idx, ok := c.find(tick)
+		for idx := c.oldest; idx >= 0; idx = c.newer[idx] {
+			if !c.alive(idx) {
+				continue
+			}
 
-			if !ok || !yield(c.keys[idx], c.vals[idx]) {
+			if !yield(idx) {
 				return
 			}
 
-			tick++
+			c.unchanged(gen)
 		}
 	}
 }
@@ -202,59 +799,429 @@ func (c *lru[K, V]) IterateAsc() iter.Seq2[K, V] {
 // Iterate all items in descending order.
 func (c *lru[K, V]) IterateDesc() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		tick := c.tick - 1
+		gen := c.gen
 
-		for range c.keys {
-			idx, ok := c.find(tick)
+		for idx := c.newest; idx >= 0; idx = c.older[idx] {
+			if !c.alive(idx) {
+				continue
+			}
 
-			if !ok || !yield(c.keys[idx], c.vals[idx]) {
+			if !yield(c.keys[idx], c.copy(c.vals[idx])) {
 				return
 			}
 
-			tick--
+			c.unchanged(gen)
 		}
 	}
 }
 
-func (c *lru[K, V]) append(key K, val V) {
-	if len(c.keys) >= cap(c.keys) {
-		c.removeOldest()
+// Iterate at most the n most recently used items, in descending order.
+func (c *lru[K, V]) IterateN(n int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if n <= 0 {
+			return
+		}
+
+		for k, v := range c.IterateDesc() {
+			if !yield(k, v) {
+				return
+			}
+
+			if n--; n == 0 {
+				return
+			}
+		}
+	}
+}
+
+// Iterate the items matching the predicate, in descending order. The predicate must not call the
+// cache.
+func (c *lru[K, V]) IterateWhere(pred func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		gen := c.gen
+
+		for idx := c.newest; idx >= 0; idx = c.older[idx] {
+			if !c.alive(idx) || !pred(c.keys[idx], c.vals[idx]) {
+				continue
+			}
+
+			if !yield(c.keys[idx], c.copy(c.vals[idx])) {
+				return
+			}
+
+			c.unchanged(gen)
+		}
+	}
+}
+
+func (c *lru[K, V]) index(key K) (idx int, ok bool) {
+	key = c.resolve(key)
+
+	for i := range c.keys {
+		if c.keys[i] == key {
+			return i, true
+		}
+	}
+
+	return
+}
+
+// Whether the item at index has not expired.
+func (c *lru[K, V]) alive(idx int) bool {
+	exp := c.deadline(idx)
+	return exp == 0 || exp > c.now()
+}
+
+func (c *lru[K, V]) now() int64 {
+	return c.clock.Now().UnixNano()
+}
+
+// Expiry timestamp of a ttl from now, or zero if the ttl never expires. A ttl of zero or less
+// falls back to WithExpireAfterWrite.
+func (c *lru[K, V]) expiry(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		if c.writeTTL <= 0 {
+			return 0
+		}
+
+		ttl = time.Duration(c.writeTTL)
+	}
+
+	return c.now() + int64(ttl)
+}
+
+// Expiry timestamp of a loaded value. An explicit ttl takes precedence over an ExpiryProvider.
+func (c *lru[K, V]) loadedExpiry(val V, ttl time.Duration) int64 {
+	if ttl <= 0 {
+		if p, ok := any(val).(ExpiryProvider); ok {
+			if t := p.ExpiresAt(); !t.IsZero() {
+				return t.UnixNano()
+			}
+		}
+	}
+
+	return c.expiry(ttl)
+}
+
+// Set or replace an item, and return whether an unexpired item was replaced.
+func (c *lru[K, V]) put(key K, val V, expires int64) (existed bool) {
+	if idx, ok := c.index(key); ok {
+		existed = c.alive(idx)
+		c.overwrite(idx, val, expires)
+		return
+	}
+
+	c.append(key, val, expires)
+	return
+}
+
+func (c *lru[K, V]) overwrite(idx int, val V, expires int64) {
+	// An expired item is replaced by a new one, which mustn't inherit its tags
+	if c.tagsOf != nil && !c.alive(idx) {
+		c.untagAll(c.keys[idx])
+	}
+
+	if expires == 0 {
+		expires = c.expiry(0)
+	}
+
+	c.vals[idx], val = c.copy(val), c.vals[idx]
+	c.promoteOn(idx, PromoteOnSet)
+	c.expires[idx] = expires
+	c.meta[idx] = c.requeue(c.meta[idx], c.newMeta())
+	c.weigh(idx)
+	c.evict(c.keys[idx], val)
+}
+
+// The least recently used item, which is next in line for eviction. Doesn't affect recency.
+func (c *lru[K, V]) Oldest() (key K, val V, ok bool) {
+	if idx := c.oldestIndex(); idx >= 0 {
+		return c.keys[idx], c.copy(c.vals[idx]), true
+	}
+
+	return
+}
+
+// The most recently used item. Doesn't affect recency.
+func (c *lru[K, V]) Newest() (key K, val V, ok bool) {
+	if idx := c.newestIndex(); idx >= 0 {
+		return c.keys[idx], c.copy(c.vals[idx]), true
+	}
+
+	return
+}
+
+// Position of an unexpired item in descending order, where 0 is the most recently used. Doesn't
+// affect recency.
+func (c *lru[K, V]) Rank(key K) (pos int, ok bool) {
+	target, ok := c.index(key)
+
+	if !ok || !c.alive(target) {
+		return 0, false
+	}
+
+	for idx := c.newest; idx != target; idx = c.older[idx] {
+		if c.alive(idx) {
+			pos++
+		}
+	}
+
+	return pos, true
+}
+
+// Remove the least recently used item, and notify its evict.
+func (c *lru[K, V]) RemoveOldest() (key K, val V, ok bool) {
+	if idx := c.oldestIndex(); idx >= 0 {
+		key, val, ok = c.keys[idx], c.vals[idx], true
+		c.removeReturned(idx)
+	}
+
+	return
+}
+
+// Remove the most recently used item, and notify its evict.
+func (c *lru[K, V]) RemoveNewest() (key K, val V, ok bool) {
+	if idx := c.newestIndex(); idx >= 0 {
+		key, val, ok = c.keys[idx], c.vals[idx], true
+		c.removeReturned(idx)
+	}
+
+	return
+}
+
+// Index of the least recently used unexpired item, or -1 if none.
+func (c *lru[K, V]) oldestIndex() (idx int) {
+	for idx = c.oldest; idx >= 0 && !c.alive(idx); {
+		idx = c.newer[idx]
+	}
+
+	return
+}
+
+// Index of the most recently used unexpired item, or -1 if none.
+func (c *lru[K, V]) newestIndex() (idx int) {
+	for idx = c.newest; idx >= 0 && !c.alive(idx); {
+		idx = c.older[idx]
+	}
+
+	return
+}
+
+// Snapshot of all keys, from least to most recently used.
+func (c *lru[K, V]) Keys() []K {
+	keys := make([]K, 0, c.Len())
+
+	for k := range c.IterateAsc() {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// Snapshot of all values, from least to most recently used.
+func (c *lru[K, V]) Values() []V {
+	vals := make([]V, 0, c.Len())
+
+	for _, v := range c.IterateAsc() {
+		vals = append(vals, v)
+	}
+
+	return vals
+}
+
+// Append a new item, and return false if there is no room for it.
+func (c *lru[K, V]) append(key K, val V, expires int64) (ok bool) {
+	key = c.canonical(key)
+	c.decayHits()
+	limit := c.limit()
+
+	if len(c.keys) > limit {
+		c.shrink(nil)
+	}
+
+	if limit <= 0 || (len(c.keys) >= limit && !c.removeOldest()) {
+		c.reject(key, val)
+		return false
+	}
+
+	if expires == 0 {
+		expires = c.expiry(0)
 	}
 
 	c.keys = append(c.keys, key)
-	c.vals = append(c.vals, val)
-	c.lastUse = append(c.lastUse, c.nextTick())
+	c.vals = append(c.vals, c.copy(val))
+	c.older = append(c.older, -1)
+	c.newer = append(c.newer, -1)
+	c.link(len(c.keys) - 1)
+	c.expires = append(c.expires, expires)
+	c.pinned = append(c.pinned, false)
+	c.meta = append(c.meta, c.newMeta())
+	c.weigh(len(c.keys) - 1)
+
+	if c.s3 != nil {
+		c.admit(len(c.keys) - 1)
+	}
+
+	if c.filter.bits != nil {
+		c.filter.add(key)
+		c.refilter()
+	}
+
+	c.emit(EventInsert, key)
+
+	if c.inserted != nil {
+		c.inserted(key, val)
+	}
+
+	return true
 }
 
-func (c *lru[K, V]) removeOldest() {
+// Evict the least recently used item that isn't pinned. Returns false if all items are pinned.
+func (c *lru[K, V]) removeOldest() (ok bool) {
 	l := len(c.keys)
 
 	if l == 0 {
-		return
+		return true
 	}
 
-	idx, ok := c.find(c.oldestTick())
+	var idx int
 
-	if ok {
-		c.remove(idx)
-	} else {
-		c.repair()
-		c.removeOldest()
+	if idx = c.oldestEvictable(); idx < 0 {
+		return false
 	}
+
+	c.evictIndex(idx)
+	return true
 }
 
-func (c *lru[K, V]) oldestTick() uint64 {
-	return c.tick - uint64(c.Len())
+// Index of the next item to evict due to capacity, or -1 if all items are pinned.
+func (c *lru[K, V]) oldestEvictable() int {
+	if c.selector != nil {
+		return c.selectVictim()
+	}
+
+	if c.samples > 0 && len(c.keys) > 0 {
+		return c.sampleVictim()
+	}
+
+	if c.cost != nil {
+		return c.cheapest()
+	}
+
+	if c.s3 != nil {
+		return c.s3Victim()
+	}
+
+	if c.mru {
+		return c.newestUnpinned()
+	}
+
+	if c.pins > 0 {
+		return c.oldestUnpinned()
+	}
+
+	return c.oldest
 }
 
-func (c *lru[K, V]) find(tick uint64) (idx int, ok bool) {
-	for i := range c.lastUse {
-		if c.lastUse[i] == tick {
-			return i, true
+// Evict an item due to capacity.
+func (c *lru[K, V]) evictIndex(idx int) {
+	key := c.keys[idx]
+
+	if c.cost != nil {
+		c.inflation = c.priority(idx)
+	}
+
+	if c.s3 != nil && c.meta[idx].small {
+		c.s3.haunt(key, c.limit()-c.smallCap())
+	}
+
+	if c.capture != nil && !c.capture.ok {
+		*c.capture = evictedItem[K, V]{key: key, val: c.vals[idx], ok: true}
+		c.removeReturned(idx)
+	} else {
+		c.remove(idx)
+	}
+
+	c.evictedByCapacity(key)
+}
+
+// Evict at most resizeStep items that exceed the capacity, or all of them with ResizeNow, as
+// configured by the last Resize, and append them to evicted unless nil. Once within capacity,
+// oversized backing arrays are released unless ResizeKeepStorage was used.
+func (c *lru[K, V]) shrink(evicted *[]EntryInfo[K, V]) {
+	limit := c.limit()
+
+	for n := 0; len(c.keys) > limit; n++ {
+		if n >= resizeStep && c.resizing&resizeNow == 0 {
+			break
 		}
+
+		idx := c.oldestEvictable()
+
+		if c.resizing&resizeNewestFirst != 0 {
+			idx = c.newestUnpinned()
+		}
+
+		if idx < 0 {
+			break
+		}
+
+		if evicted != nil {
+			*evicted = append(*evicted, c.entryInfo(idx, c.now()))
+		}
+
+		c.evictIndex(idx)
 	}
 
-	return
+	if limit = max(limit, 0); len(c.keys) > limit {
+		return
+	}
+
+	flags := c.resizing
+	c.resizing = 0
+
+	if flags&resizeKeepStorage == 0 && cap(c.keys) > 2*max(limit, resizeStep) {
+		c.keys = realloc(c.keys, limit)
+		c.vals = realloc(c.vals, limit)
+		c.older = realloc(c.older, limit)
+		c.newer = realloc(c.newer, limit)
+		c.expires = realloc(c.expires, limit)
+		c.pinned = realloc(c.pinned, limit)
+		c.meta = realloc(c.meta, limit)
+	}
+}
+
+// Maximum number of items. A capacity of zero or less is either unbounded or disabled, depending
+// on WithUnbounded. A closed cache is always disabled.
+func (c *lru[K, V]) limit() int {
+	if c.closed {
+		return 0
+	}
+
+	if c.capacity <= 0 && c.unbounded {
+		return math.MaxInt
+	}
+
+	return c.capacity
+}
+
+// Copy a value crossing the cache boundary, if a copier is used.
+func (c *lru[K, V]) copy(val V) V {
+	if c.copier != nil {
+		return c.copier(val)
+	}
+
+	return val
+}
+
+func (c *lru[K, V]) reject(key K, val V) {
+	if c.rejected != nil {
+		c.rejected(key, val)
+	}
+}
+
+func realloc[T any](s []T, capacity int) []T {
+	return append(make([]T, 0, capacity), s...)
 }
 
 func (c *lru[K, V]) remove(idx int) {
@@ -265,54 +1232,189 @@ func (c *lru[K, V]) remove(idx int) {
 
 	end := len(c.keys) - 1
 
+	if c.s3 != nil {
+		c.dequeue(idx)
+	}
+
+	c.unlink(idx)
+
+	// Move the last item into the slot, and point its neighbours to it
+	if idx != end {
+		older, newer := c.older[end], c.newer[end]
+		c.older[idx], c.newer[idx] = older, newer
+
+		if older >= 0 {
+			c.newer[older] = idx
+		} else {
+			c.oldest = idx
+		}
+
+		if newer >= 0 {
+			c.older[newer] = idx
+		} else {
+			c.newest = idx
+		}
+
+		if c.s3 != nil && c.s3.split == end {
+			c.s3.split = idx
+		}
+	}
+
 	// Swap with zero values
 	key, c.keys[idx], c.keys[end] = c.keys[idx], c.keys[end], key
 	val, c.vals[idx], c.vals[end] = c.vals[idx], c.vals[end], val
-	c.lastUse[idx], c.lastUse[end] = c.lastUse[end], c.lastUse[idx]
+	c.expires[idx], c.expires[end] = c.expires[end], 0
+
+	if c.pinned[idx] {
+		c.pins--
+	}
+
+	c.pinned[idx], c.pinned[end] = c.pinned[end], false
+	c.meta[idx], c.meta[end] = c.meta[end], entryMeta{}
 
 	c.keys = c.keys[:end]
 	c.vals = c.vals[:end]
-	c.lastUse = c.lastUse[:end]
+	c.older = c.older[:end]
+	c.newer = c.newer[:end]
+	c.expires = c.expires[:end]
+	c.pinned = c.pinned[:end]
+	c.meta = c.meta[:end]
+
+	if c.filter.bits != nil {
+		c.filter.stale++
+		c.refilter()
+	}
+
+	c.unaliasAll(key)
+
+	if c.tagsOf != nil {
+		c.untagAll(key)
+	}
 
 	c.evict(key, val)
 }
 
 func (c *lru[K, V]) evict(key K, val V) {
+	if len(c.leases) > 0 && c.deferEvict(key, val, true) {
+		return
+	}
+
 	if c.evicted != nil {
 		c.evicted(key, val)
 	}
-}
 
-func (c *lru[K, V]) nextTick() uint64 {
-	c.preventTickOverflow()
-	idx := c.tick
-	c.tick++
-	return idx
+	c.releaseValue(val)
 }
 
-func (c *lru[K, V]) preventTickOverflow() {
-	if c.tick != math.MaxUint64 {
+// Release a value that leaves the cache without an evict notice.
+func (c *lru[K, V]) drop(key K, val V) {
+	if len(c.leases) > 0 && c.deferEvict(key, val, false) {
 		return
 	}
 
-	tick := c.oldestTick()
+	c.releaseValue(val)
+}
+
+func (c *lru[K, V]) releaseValue(val V) {
+	if c.releaser != nil && !c.retain {
+		c.releaser(val)
+	}
+}
 
-	for i := range c.lastUse {
-		if c.lastUse[i] < tick {
-			c.repair()
-			return
-		}
+// Store a new value of an unexpired item, and release the old value without an evict notice,
+// unless it's the same value.
+func (c *lru[K, V]) replaceValue(idx int, val V) {
+	old := c.vals[idx]
+	c.vals[idx] = val
 
-		c.lastUse[i] += tick
+	if c.releaser != nil && !sameValue(old, val) {
+		c.drop(c.keys[idx], old)
 	}
+}
+
+// Whether two values are identical, so that storing one over the other mustn't release it.
+// Slices, maps and functions are identical when they point to the same memory.
+func sameValue[V any](a, b V) bool {
+	va, vb := reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem()
 
-	c.tick = uint64(c.Len())
+	switch va.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Func:
+		return va.Pointer() == vb.Pointer()
+	}
+
+	return va.Comparable() && va.Equal(vb)
+}
+
+// Remove an item whose value is returned to the caller, and thereby mustn't be released.
+func (c *lru[K, V]) removeReturned(idx int) {
+	c.retain = true
+	c.remove(idx)
+	c.retain = false
+}
+
+// Mark an item as most recently used.
+func (c *lru[K, V]) promote(idx int) {
+	c.decayHits()
+
+	if c.samples > 0 {
+		c.meta[idx].stamp = c.nextStamp()
+	} else if c.s3 == nil && c.newest != idx {
+		c.unlink(idx)
+		c.link(idx)
+	}
+
+	if c.cost != nil {
+		c.meta[idx].base = c.inflation
+	}
+
+	if c.idle > 0 {
+		atomic.StoreInt64(&c.meta[idx].accessed, c.now())
+	}
+}
+
+// Mark an item as most recently used, if the operation promotes.
+func (c *lru[K, V]) promoteOn(idx int, op PromoteOn) {
+	if c.noPromote&op == 0 {
+		c.promote(idx)
+	}
+}
+
+// Panic if the recency order has changed since a generation, which would corrupt an iteration.
+func (c *lru[K, V]) unchanged(gen uint64) {
+	if c.gen != gen {
+		panic("lru: cache changed during iteration")
+	}
+}
+
+// Link an unlinked item as the most recently used.
+func (c *lru[K, V]) link(idx int) {
+	c.gen++
+	c.older[idx] = c.newest
+	c.newer[idx] = -1
+
+	if c.newest >= 0 {
+		c.newer[c.newest] = idx
+	} else {
+		c.oldest = idx
+	}
+
+	c.newest = idx
 }
 
-func (c *lru[K, V]) repair() {
-	for i := range c.lastUse {
-		c.lastUse[i] = uint64(i)
+// Unlink an item from the recency order.
+func (c *lru[K, V]) unlink(idx int) {
+	c.gen++
+	older, newer := c.older[idx], c.newer[idx]
+
+	if older >= 0 {
+		c.newer[older] = newer
+	} else {
+		c.oldest = newer
 	}
 
-	c.tick = uint64(c.Len())
+	if newer >= 0 {
+		c.older[newer] = older
+	} else {
+		c.newest = older
+	}
 }
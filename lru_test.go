@@ -2,7 +2,11 @@ package lru
 
 import (
 	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
 	"testing"
+	"time"
 )
 
 func Example() {
@@ -66,3 +70,1162 @@ func BenchmarkThreadsafe(b *testing.B) {
 		})
 	}
 }
+
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1700000000, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestGetOrSetTTL(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewWithOptions(8, WithClock[int, int](clock))
+	calls := 0
+	setter := func(k int) (int, error) {
+		calls++
+		return k * 10, nil
+	}
+
+	if v, _ := cache.GetOrSetTTL(1, time.Millisecond, setter); v != 10 {
+		t.Fatalf("expected 10, got %d", v)
+	}
+
+	if v, _ := cache.GetOrSetTTL(1, time.Millisecond, setter); v != 10 || calls != 1 {
+		t.Fatalf("expected cached value, got %d after %d calls", v, calls)
+	}
+
+	clock.Advance(time.Millisecond)
+
+	if cache.Has(1) {
+		t.Fatal("expected item to be expired")
+	}
+
+	if _, ok := cache.Get(1); ok {
+		t.Fatal("expected expired item to be missing")
+	}
+
+	_, _ = cache.GetOrSetTTL(1, 0, setter)
+
+	if calls != 2 {
+		t.Fatalf("expected setter to be called again, got %d calls", calls)
+	}
+
+	if cache.Len() != 1 {
+		t.Fatalf("expected expired item to be overwritten, got %d items", cache.Len())
+	}
+}
+
+func TestGetOrSetExpiring(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewWithOptions(8, WithClock[int, int](clock))
+
+	_, _ = cache.GetOrSetExpiring(1, func(k int) (int, time.Duration, error) {
+		return k, time.Millisecond, nil
+	})
+
+	_, _ = cache.GetOrSetExpiring(2, func(k int) (int, time.Duration, error) {
+		return k, 0, nil
+	})
+
+	clock.Advance(time.Millisecond)
+
+	if cache.Has(1) {
+		t.Fatal("expected item 1 to be expired")
+	}
+
+	if !cache.Has(2) {
+		t.Fatal("expected item 2 to never expire")
+	}
+}
+
+func ExampleLRU_Keys() {
+	cache := New[string, int](3)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Get("a")
+
+	fmt.Println(cache.Keys())
+	fmt.Println(cache.Values())
+
+	// Output:
+	//
+	// [b c a]
+	// [2 3 1]
+}
+
+func TestCallOptions(t *testing.T) {
+	cache := New[int, int](8)
+	cache.Set(1, 1)
+
+	if _, ok := cache.Get(1, Bypass()); ok {
+		t.Fatal("expected bypassed Get to miss")
+	}
+
+	v, _ := cache.GetOrSet(2, func(k int) (int, error) { return k, nil }, Bypass())
+
+	if v != 2 || cache.Has(2) {
+		t.Fatal("expected bypassed GetOrSet to load without storing")
+	}
+
+	v, _ = cache.GetOrSet(1, func(int) (int, error) { return 10, nil }, ForceRefresh())
+
+	if v != 10 {
+		t.Fatalf("expected refreshed value 10, got %d", v)
+	}
+
+	if v, _ := cache.Get(1); v != 10 {
+		t.Fatalf("expected refreshed value to be stored, got %d", v)
+	}
+}
+
+func ExampleLRU_Oldest() {
+	cache := New[string, int](3)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Get("a")
+
+	fmt.Println(cache.Oldest())
+	fmt.Println(cache.Newest())
+
+	// Output:
+	//
+	// b 2 true
+	// a 1 true
+}
+
+func TestRemoveOldest(t *testing.T) {
+	var evicted []int
+
+	cache := New(4, func(key int, _ int) {
+		evicted = append(evicted, key)
+	})
+
+	for i := 1; i <= 4; i++ {
+		cache.Set(i, i)
+	}
+
+	cache.Get(1)
+
+	if k, _, ok := cache.RemoveOldest(); !ok || k != 2 {
+		t.Fatalf("expected 2 to be removed, got %d", k)
+	}
+
+	if k, _, ok := cache.RemoveNewest(); !ok || k != 1 {
+		t.Fatalf("expected 1 to be removed, got %d", k)
+	}
+
+	if cache.Len() != 2 || len(evicted) != 2 {
+		t.Fatalf("expected 2 items left and 2 evicts, got %d and %d", cache.Len(), len(evicted))
+	}
+
+	cache.Reset()
+
+	if _, _, ok := cache.RemoveOldest(); ok {
+		t.Fatal("expected nothing to remove from an empty cache")
+	}
+}
+
+type expiringValue time.Time
+
+func (v expiringValue) ExpiresAt() time.Time {
+	return time.Time(v)
+}
+
+func TestExpiryProvider(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewWithOptions(8, WithClock[int, expiringValue](clock))
+
+	_, _ = cache.GetOrSet(1, func(int) (expiringValue, error) {
+		return expiringValue(clock.Now().Add(time.Millisecond)), nil
+	})
+
+	_, _ = cache.GetOrSet(2, func(int) (expiringValue, error) {
+		return expiringValue{}, nil
+	})
+
+	_, _ = cache.GetOrSetTTL(3, time.Hour, func(int) (expiringValue, error) {
+		return expiringValue(clock.Now().Add(time.Millisecond)), nil
+	})
+
+	clock.Advance(time.Millisecond)
+
+	if cache.Has(1) {
+		t.Fatal("expected item 1 to expire at its own expiry")
+	}
+
+	if !cache.Has(2) {
+		t.Fatal("expected item 2 without expiry to never expire")
+	}
+
+	if !cache.Has(3) {
+		t.Fatal("expected the explicit ttl of item 3 to take precedence")
+	}
+}
+
+func BenchmarkGetOrSet(b *testing.B) {
+	cache := New[int, int](64)
+
+	for i := range b.N {
+		_, _ = cache.GetOrSet(i%128, func(k int) (int, error) { return k, nil })
+	}
+}
+
+func TestResizeIncremental(t *testing.T) {
+	cache := New[int, int](200)
+
+	for i := range 200 {
+		cache.Set(i, i)
+	}
+
+	cache.Resize(10)
+
+	if l := cache.Len(); l != 200-resizeStep {
+		t.Fatalf("expected %d items after resize, got %d", 200-resizeStep, l)
+	}
+
+	for i := 200; cache.Len() > 10; i++ {
+		cache.Set(i, i)
+	}
+
+	if _, ok := cache.Get(199); !ok {
+		t.Fatal("expected the most recent items to survive the shrink")
+	}
+
+	cache.Resize(20)
+
+	for i := range 20 {
+		cache.Set(1000+i, i)
+	}
+
+	if l := cache.Len(); l != 20 {
+		t.Fatalf("expected 20 items after growing, got %d", l)
+	}
+}
+
+func TestResizeOptions(t *testing.T) {
+	fill := func() LRU[int, int] {
+		cache := New[int, int](10)
+
+		for i := range 10 {
+			cache.Set(i, i)
+		}
+
+		return cache
+	}
+
+	cache := fill()
+	evicted := cache.Resize(7)
+
+	if len(evicted) != 3 || evicted[0].Key != 0 || evicted[2].Key != 2 || evicted[2].Value != 2 {
+		t.Fatalf("expected the 3 oldest items to be returned, got %+v", evicted)
+	}
+
+	cache = fill()
+
+	if evicted := cache.Resize(5, ResizeLazy()); evicted != nil || cache.Len() != 10 {
+		t.Fatalf("expected no evictions right away, got %+v", evicted)
+	}
+
+	if cache.Set(10, 10); cache.Len() != 5 || cache.Has(5) || !cache.Has(6) {
+		t.Fatalf("expected the next insert to shrink the cache, got %v", cache.Keys())
+	}
+
+	cache = fill()
+	evicted = cache.Resize(7, ResizeEvictNewestFirst())
+
+	if len(evicted) != 3 || evicted[0].Key != 9 || !slices.Equal(cache.Keys(), []int{0, 1, 2, 3, 4, 5, 6}) {
+		t.Fatalf("expected the 3 newest items to be evicted, got %+v", evicted)
+	}
+}
+
+func TestResizeNow(t *testing.T) {
+	cache := New[int, int](1000)
+
+	for i := range 500 {
+		cache.Set(i, i)
+	}
+
+	if evicted := cache.Resize(400); len(evicted) != resizeStep || cache.Len() != 500-resizeStep {
+		t.Fatalf("expected a single step of evictions, got %d", len(evicted))
+	}
+
+	if evicted := cache.Resize(10, ResizeNow()); len(evicted) != 490-resizeStep || cache.Len() != 10 || !cache.Has(499) {
+		t.Fatalf("expected all excess items to be evicted right away, got %d", len(evicted))
+	}
+}
+
+func TestResizeKeepStorage(t *testing.T) {
+	cache := New[int, int](1000).(*lru[int, int])
+
+	for i := range 100 {
+		cache.Set(i, i)
+	}
+
+	cache.Resize(10, ResizeKeepStorage())
+
+	for i := 100; cache.Len() > 10; i++ {
+		cache.Set(i, i)
+	}
+
+	if cap(cache.keys) != 1000 {
+		t.Fatalf("expected the storage to be kept, got a capacity of %d", cap(cache.keys))
+	}
+
+	cache.Resize(5)
+
+	if cap(cache.keys) != 5 {
+		t.Fatalf("expected the storage to be released, got a capacity of %d", cap(cache.keys))
+	}
+}
+
+func TestReleaser(t *testing.T) {
+	var released []int
+
+	cache := NewWithOptions(2, WithReleaser[string](func(val *int) {
+		released = append(released, *val)
+	}))
+
+	val := func(v int) *int {
+		return &v
+	}
+
+	cache.Set("a", val(1))
+	cache.Set("b", val(2))
+	cache.Set("c", val(3))
+	cache.Replace("b", val(4))
+	cache.Remove("c")
+
+	if !slices.Equal(released, []int{1, 2, 3}) {
+		t.Fatalf("expected evicted, overwritten and removed values to be released, got %v", released)
+	}
+
+	if v, ok := cache.GetAndRemove("b"); !ok || *v != 4 || len(released) != 3 {
+		t.Fatalf("expected a returned value to not be released, got %v", released)
+	}
+}
+
+func TestReleaserUpdate(t *testing.T) {
+	var released []int
+
+	val := func(v int) *int {
+		return &v
+	}
+
+	cache := NewWithOptions(4, WithReleaser[string](func(val *int) {
+		released = append(released, *val)
+	}))
+
+	cache.Upsert("a", val(1))
+	cache.Upsert("a", val(2))
+
+	same := val(3)
+	cache.Upsert("a", same)
+	cache.Upsert("a", same)
+
+	if !slices.Equal(released, []int{1, 2}) {
+		t.Fatalf("expected values overwritten by Upsert to be released, got %v", released)
+	}
+
+	if old, _ := cache.Swap("a", val(4)); *old != 3 || len(released) != 2 {
+		t.Fatalf("expected a swapped value to not be released, got %v", released)
+	}
+
+	_, version, _ := cache.GetVersioned("a")
+	cache.CompareAndSwap("a", version, val(5))
+
+	cache.Compute("a", func(old *int, _ bool) (*int, bool) {
+		return val(*old + 1), false
+	})
+
+	cache.Compute("a", func(old *int, _ bool) (*int, bool) {
+		*old++
+		return old, false
+	})
+
+	if !slices.Equal(released, []int{1, 2, 4, 5}) {
+		t.Fatalf("expected values overwritten by CompareAndSwap and Compute to be released, got %v", released)
+	}
+
+	other := New[string, *int](4)
+	other.Set("a", val(10))
+
+	cache.Merge(other, nil)
+
+	if !slices.Equal(released, []int{1, 2, 4, 5, 7}) {
+		t.Fatalf("expected a value overwritten by Merge to be released, got %v", released)
+	}
+}
+
+func TestMergeCopier(t *testing.T) {
+	cache := NewWithOptions(4, WithCopier[string](slices.Clone[[]int]))
+	other := New[string, []int](4)
+
+	v := []int{1}
+	other.Set("a", v)
+	cache.Merge(other, nil)
+	v[0] = 2
+
+	if got, _ := cache.Get("a"); got[0] != 1 {
+		t.Fatalf("expected merged values to be copied, got %v", got)
+	}
+}
+
+func TestIterateOrder(t *testing.T) {
+	cache := New[int, int](8)
+
+	for i := range 5 {
+		cache.Set(i, i)
+	}
+
+	cache.Get(4)
+	cache.Get(4)
+	cache.Get(1)
+	cache.Remove(2)
+
+	want := []int{0, 3, 4, 1}
+
+	if keys := cache.Keys(); !slices.Equal(keys, want) {
+		t.Fatalf("expected ascending %v, got %v", want, keys)
+	}
+
+	slices.Reverse(want)
+
+	var keys []int
+
+	for k := range cache.IterateDesc() {
+		keys = append(keys, k)
+	}
+
+	if !slices.Equal(keys, want) {
+		t.Fatalf("expected descending %v, got %v", want, keys)
+	}
+}
+
+func TestIterateNWhere(t *testing.T) {
+	for _, cache := range []LRU[int, int]{New[int, int](16), NewThreadSafe[int, int](16)} {
+		for i := range 10 {
+			cache.Set(i, i)
+		}
+
+		var keys []int
+
+		for k := range cache.IterateN(3) {
+			keys = append(keys, k)
+		}
+
+		if want := []int{9, 8, 7}; !slices.Equal(keys, want) {
+			t.Fatalf("expected the newest %v, got %v", want, keys)
+		}
+
+		keys = keys[:0]
+
+		for k := range cache.IterateWhere(func(k, _ int) bool { return k%3 == 0 }) {
+			keys = append(keys, k)
+		}
+
+		if want := []int{9, 6, 3, 0}; !slices.Equal(keys, want) {
+			t.Fatalf("expected matching %v, got %v", want, keys)
+		}
+
+		for range cache.IterateN(0) {
+			t.Fatal("expected no items")
+		}
+	}
+}
+
+func TestOrderGuarantees(t *testing.T) {
+	var evicted []int
+
+	cache := New(5, func(key, _ int) {
+		evicted = append(evicted, key)
+	})
+
+	for i := range 5 {
+		cache.Set(i, i)
+	}
+
+	cache.Set(0, 10)
+	cache.ContainsOrAdd(1, 10)
+	cache.Has(2)
+	cache.Replace(3, 10)
+
+	if want := []int{0, 1, 2, 4, 3}; !slices.Equal(cache.Keys(), want) {
+		t.Fatalf("expected only Replace to reorder, got %v", cache.Keys())
+	}
+
+	// Replace notifies the overwritten value
+	evicted = evicted[:0]
+
+	cache.Pin(0)
+	cache.Set(5, 5)
+	cache.Set(6, 6)
+
+	if want := []int{1, 2}; !slices.Equal(evicted, want) {
+		t.Fatalf("expected eviction in order, skipping pinned items, got %v", evicted)
+	}
+
+	if want := []int{0, 4, 3, 5, 6}; !slices.Equal(cache.Keys(), want) {
+		t.Fatalf("expected a pinned item to keep its position, got %v", cache.Keys())
+	}
+
+	cache.Resize(3)
+	cache.Resize(8)
+
+	if want := []int{1, 2, 4, 3}; !slices.Equal(evicted, want) {
+		t.Fatalf("expected Resize to evict in order, got %v", evicted)
+	}
+
+	if want := []int{0, 5, 6}; !slices.Equal(cache.Keys(), want) {
+		t.Fatalf("expected Resize to keep the order, got %v", cache.Keys())
+	}
+
+	// Equal priorities evict the least recently used
+	cost := NewWithOptions(3, WithCostFn(func(int, int) float64 { return 1 }))
+
+	for i := range 4 {
+		cost.Set(i, i)
+	}
+
+	if want := []int{1, 2, 3}; !slices.Equal(cost.Keys(), want) {
+		t.Fatalf("expected ties to evict the oldest, got %v", cost.Keys())
+	}
+}
+
+func TestRank(t *testing.T) {
+	for _, cache := range []LRU[int, int]{New[int, int](8), NewThreadSafe[int, int](8)} {
+		for i := range 5 {
+			cache.Set(i, i)
+		}
+
+		cache.Get(1)
+		cache.Remove(3)
+
+		for key, want := range map[int]int{1: 0, 4: 1, 2: 2, 0: 3} {
+			if pos, ok := cache.Rank(key); !ok || pos != want {
+				t.Fatalf("expected %d at rank %d, got %d", key, want, pos)
+			}
+		}
+
+		if _, ok := cache.Rank(3); ok {
+			t.Fatal("expected a removed item to have no rank")
+		}
+	}
+}
+
+func TestEvictionAfterRemovals(t *testing.T) {
+	var evicted []int
+
+	cache := New(4, func(key, _ int) {
+		evicted = append(evicted, key)
+	})
+
+	for i := range 4 {
+		cache.Set(i, i)
+	}
+
+	cache.Remove(1)
+	cache.RemoveFunc(func(key, _ int) bool {
+		return key == 2
+	})
+
+	evicted = nil
+
+	for i := 4; i < 8; i++ {
+		cache.Set(i, i)
+	}
+
+	if !slices.Equal(evicted, []int{0, 3}) || !slices.Equal(cache.Keys(), []int{4, 5, 6, 7}) {
+		t.Fatalf("expected the oldest items to be evicted in order, got %v and kept %v", evicted, cache.Keys())
+	}
+}
+
+// Compare the recency order against a trivial model, over random operations.
+func TestRecencyModel(t *testing.T) {
+	const capacity = 8
+
+	rnd := rand.New(rand.NewPCG(1, 2))
+	cache := New[int, int](capacity)
+
+	var model []int // From least to most recently used
+
+	touch := func(key int) {
+		if i := slices.Index(model, key); i >= 0 {
+			model = slices.Delete(model, i, i+1)
+		}
+
+		model = append(model, key)
+	}
+
+	for range 10000 {
+		key := rnd.IntN(16)
+
+		switch rnd.IntN(4) {
+		case 0:
+			if _, ok := cache.Get(key); ok {
+				touch(key)
+			}
+
+		case 1:
+			if i := slices.Index(model, key); cache.Remove(key) != (i >= 0) {
+				t.Fatalf("unexpected removal of %d", key)
+			} else if i >= 0 {
+				model = slices.Delete(model, i, i+1)
+			}
+
+		default:
+			if cache.Replace(key, key); !slices.Contains(model, key) && len(model) == capacity {
+				model = model[1:]
+			}
+
+			touch(key)
+		}
+
+		if keys := cache.Keys(); !slices.Equal(keys, model) {
+			t.Fatalf("expected %v, got %v", model, keys)
+		}
+	}
+}
+
+func TestTouch(t *testing.T) {
+	cache := New[int, int](2)
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+
+	if !cache.Touch(1) || cache.Touch(3) {
+		t.Fatal("expected only existing items to be touched")
+	}
+
+	cache.Set(3, 3)
+
+	if !cache.Has(1) || cache.Has(2) {
+		t.Fatal("expected the touched item to survive eviction")
+	}
+
+	if s := cache.Stats(); s.Hits != 0 || s.Misses != 0 {
+		t.Fatalf("expected touches to not count as lookups, got %+v", s)
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	var evicted int
+
+	cache := New(2, func(int, int) {
+		evicted++
+	})
+
+	if cache.Upsert(1, 1) {
+		t.Fatal("expected a new item to not be reported as updated")
+	}
+
+	cache.Set(2, 2)
+
+	if !cache.Upsert(1, 10) {
+		t.Fatal("expected an existing item to be updated")
+	}
+
+	if v, _ := cache.Get(1); v != 10 || evicted != 0 {
+		t.Fatalf("expected updated value without evict, got %d and %d evicts", v, evicted)
+	}
+
+	cache.Upsert(2, 20)
+	cache.Set(3, 3)
+
+	if cache.Has(1) || !cache.Has(2) {
+		t.Fatal("expected upsert to refresh recency")
+	}
+}
+
+func TestGetOrSetValue(t *testing.T) {
+	cache := New[int, int](2)
+
+	if v, loaded := cache.GetOrSetValue(1, 1); loaded || v != 1 {
+		t.Fatalf("expected 1 to be stored, got %d (loaded: %v)", v, loaded)
+	}
+
+	if v, loaded := cache.GetOrSetValue(1, 2); !loaded || v != 1 {
+		t.Fatalf("expected 1 to be loaded, got %d (loaded: %v)", v, loaded)
+	}
+}
+
+func TestZeroCapacity(t *testing.T) {
+	var rejected []int
+
+	cache := NewWithOptions(0, WithRejected(func(key int, _ int) {
+		rejected = append(rejected, key)
+	}))
+
+	if cache.Set(1, 1) || cache.Replace(2, 2) || cache.Len() != 0 {
+		t.Fatal("expected a zero capacity cache to reject all items")
+	}
+
+	if len(rejected) != 2 {
+		t.Fatalf("expected 2 rejected items, got %v", rejected)
+	}
+
+	if v, _ := cache.GetOrSet(3, func(k int) (int, error) { return k, nil }); v != 3 || cache.Has(3) {
+		t.Fatal("expected GetOrSet to return the value without storing it")
+	}
+}
+
+func TestUnbounded(t *testing.T) {
+	cache := NewWithOptions(0, WithUnbounded[int, int]())
+
+	for i := range 1000 {
+		cache.Set(i, i)
+	}
+
+	if cache.Len() != 1000 {
+		t.Fatalf("expected an unbounded cache to keep all items, got %d", cache.Len())
+	}
+
+	cache.Resize(10)
+
+	for i := range 100 {
+		cache.Set(1000+i, i)
+	}
+
+	if cache.Len() != 10 {
+		t.Fatalf("expected resized cache to be bounded, got %d", cache.Len())
+	}
+}
+
+func TestTinyCaches(t *testing.T) {
+	if c := New[int, int](8); c.Cap() != 8 {
+		t.Fatalf("expected the configured capacity of an empty cache, got %d", c.Cap())
+	}
+
+	var evicted []int
+
+	one := New(1, func(key, _ int) {
+		evicted = append(evicted, key)
+	})
+
+	one.Set(1, 1)
+	one.Set(2, 2)
+	one.Resize(1)
+
+	if one.Cap() != 1 || one.Len() != 1 || one.Has(1) || !one.Has(2) || !slices.Equal(evicted, []int{1}) {
+		t.Fatalf("expected only the newest item in a cache of 1, got %v (evicted %v)", one.Keys(), evicted)
+	}
+
+	if one.Pin(2); one.Set(3, 3) || !one.Has(2) {
+		t.Fatal("expected a pinned item to reject new items in a cache of 1")
+	}
+
+	two := New[int, int](2)
+	two.Set(1, 1)
+	two.Set(2, 2)
+	two.Get(1)
+	two.Set(3, 3)
+
+	if keys := two.Keys(); !slices.Equal(keys, []int{1, 3}) {
+		t.Fatalf("expected the least recently used item to be evicted, got %v", keys)
+	}
+
+	two.Resize(1)
+
+	if keys := two.Keys(); two.Cap() != 1 || !slices.Equal(keys, []int{3}) {
+		t.Fatalf("expected shrinking to keep the newest item, got %v", keys)
+	}
+
+	two.Resize(2)
+	two.Set(4, 4)
+
+	if keys := two.Keys(); two.Cap() != 2 || !slices.Equal(keys, []int{3, 4}) {
+		t.Fatalf("expected growing to make room again, got %v", keys)
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	cache := New[int, int](4)
+
+	for i := range 4 {
+		cache.Set(i, i)
+	}
+
+	if cache.Available() != 0 {
+		t.Fatalf("expected a full cache, got %d available", cache.Available())
+	}
+
+	cache.Resize(2)
+	cache.Resize(4)
+
+	if cache.Len() != 2 || cache.Cap() != 4 || cache.Available() != 2 {
+		t.Fatalf("expected room for 2 after shrinking and growing, got %d of %d", cache.Len(), cache.Cap())
+	}
+
+	if n := NewWithOptions(0, WithUnbounded[int, int]()).Available(); n != math.MaxInt {
+		t.Fatalf("expected an unbounded cache to have unlimited room, got %d", n)
+	}
+
+	if n := New[int, int](0).Available(); n != 0 {
+		t.Fatalf("expected a disabled cache to have no room, got %d", n)
+	}
+}
+
+func benchmarkCaps(b *testing.B, fn func(b *testing.B, capacity int)) {
+	for i := 8; i <= 512; i *= 2 {
+		b.Run(fmt.Sprintf("cap_%03d", i), func(b *testing.B) {
+			fn(b, i)
+		})
+	}
+}
+
+func filled(capacity int, evicted ...func(int, int)) LRU[int, int] {
+	cache := New(capacity, evicted...)
+
+	for i := range capacity {
+		cache.Set(i, i)
+	}
+
+	return cache
+}
+
+func BenchmarkGetHit(b *testing.B) {
+	benchmarkCaps(b, func(b *testing.B, capacity int) {
+		cache := filled(capacity)
+		b.ResetTimer()
+
+		for i := range b.N {
+			cache.Get(i % capacity)
+		}
+	})
+}
+
+func BenchmarkGetMiss(b *testing.B) {
+	benchmarkCaps(b, func(b *testing.B, capacity int) {
+		cache := filled(capacity)
+		b.ResetTimer()
+
+		for i := range b.N {
+			cache.Get(capacity + i)
+		}
+	})
+}
+
+func BenchmarkMixed(b *testing.B) {
+	for _, reads := range []int{50, 90, 99} {
+		b.Run(fmt.Sprintf("reads_%02d", reads), func(b *testing.B) {
+			benchmarkCaps(b, func(b *testing.B, capacity int) {
+				cache := filled(capacity)
+				b.ResetTimer()
+
+				for i := range b.N {
+					// Keys span twice the capacity, so about half of the reads miss
+					key := (i * 7) % (capacity * 2)
+
+					if i%100 < reads {
+						cache.Get(key)
+					} else {
+						cache.Replace(key, i)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkIterateAsc(b *testing.B) {
+	benchmarkCaps(b, func(b *testing.B, capacity int) {
+		cache := filled(capacity)
+		b.ResetTimer()
+
+		for range b.N {
+			for range cache.IterateAsc() {
+			}
+		}
+	})
+}
+
+func BenchmarkRemove(b *testing.B) {
+	benchmarkCaps(b, func(b *testing.B, capacity int) {
+		cache := filled(capacity)
+		b.ResetTimer()
+
+		for i := range b.N {
+			key := i % capacity
+			cache.Remove(key)
+			cache.Set(key, i)
+		}
+	})
+}
+
+func BenchmarkEvictCallback(b *testing.B) {
+	var evicted int
+
+	benchmarkCaps(b, func(b *testing.B, capacity int) {
+		cache := filled(capacity, func(int, int) { evicted++ })
+		b.ResetTimer()
+
+		for i := range b.N {
+			cache.Set(capacity+i, i)
+		}
+	})
+}
+
+func TestRemoveFunc(t *testing.T) {
+	var evicted []int
+
+	cache := New(8, func(k int, _ int) {
+		evicted = append(evicted, k)
+	})
+
+	for i := range 8 {
+		cache.Set(i, i)
+	}
+
+	if n := cache.RemoveFunc(func(k, _ int) bool { return k%2 == 0 }); n != 4 {
+		t.Fatalf("expected 4 removed items, got %d", n)
+	}
+
+	if cache.Len() != 4 || len(evicted) != 4 {
+		t.Fatalf("expected 4 remaining items and 4 evicts, got %d and %d", cache.Len(), len(evicted))
+	}
+
+	for k := range cache.Iterate() {
+		if k%2 == 0 {
+			t.Fatalf("expected %d to be removed", k)
+		}
+	}
+}
+
+func TestCopier(t *testing.T) {
+	cache := NewWithOptions(4, WithCopier[int, []int](slices.Clone))
+	val := []int{1, 2}
+
+	cache.Set(1, val)
+	val[0] = 10
+
+	got, _ := cache.Get(1)
+	got[1] = 20
+
+	for _, v := range cache.Iterate() {
+		v[0] = 30
+	}
+
+	if got, _ := cache.Get(1); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("expected the cached value to be unaffected by mutations, got %v", got)
+	}
+}
+
+func ExampleLRU_Warm() {
+	cache := New[string, int](2)
+
+	cache.Warm(func(yield func(string, int) bool) {
+		for i, k := range []string{"a", "b", "c"} {
+			if !yield(k, i) {
+				return
+			}
+		}
+	})
+
+	fmt.Println(cache.Keys())
+
+	// Output: [b c]
+}
+
+func TestSwapAndGetAndRemove(t *testing.T) {
+	var evicted []int
+
+	cache := New(4, func(_ int, v int) {
+		evicted = append(evicted, v)
+	})
+
+	if _, existed := cache.Swap(1, 1); existed {
+		t.Fatal("expected a new item to not exist")
+	}
+
+	if old, existed := cache.Swap(1, 2); !existed || old != 1 || len(evicted) != 0 {
+		t.Fatalf("expected the previous value without evict, got %d", old)
+	}
+
+	if v, ok := cache.GetAndRemove(1); !ok || v != 2 || cache.Has(1) {
+		t.Fatalf("expected the removed value, got %d", v)
+	}
+
+	if _, ok := cache.GetAndRemove(1); ok {
+		t.Fatal("expected a missing item")
+	}
+}
+
+func TestContainsOrAdd(t *testing.T) {
+	cache := NewThreadSafe[int, int](2)
+
+	if existed, evicted := cache.ContainsOrAdd(1, 1); existed || evicted {
+		t.Fatal("expected a new item without eviction")
+	}
+
+	cache.Set(2, 2)
+
+	if prev, existed, evicted := cache.PeekOrAdd(1, 10); !existed || evicted || prev != 1 {
+		t.Fatalf("expected the existing value, got %d", prev)
+	}
+
+	// The peek didn't promote 1, so it's evicted
+	if existed, evicted := cache.ContainsOrAdd(3, 3); existed || !evicted || cache.Has(1) {
+		t.Fatal("expected the oldest item to be evicted")
+	}
+
+	if !cache.Add(4, 4) || cache.Add(4, 40) {
+		t.Fatal("expected Add to report evictions")
+	}
+}
+
+func TestSetEvict(t *testing.T) {
+	cache := New[int, string](2)
+
+	cache.Set(1, "a")
+
+	if _, _, evicted := cache.SetEvict(2, "b"); evicted {
+		t.Fatal("expected no eviction below capacity")
+	}
+
+	if k, v, evicted := cache.ReplaceEvict(3, "c"); !evicted || k != 1 || v != "a" {
+		t.Fatalf("expected the oldest item to be returned, got %d and %q", k, v)
+	}
+}
+
+func TestIterateChanged(t *testing.T) {
+	cache := New[int, int](4)
+
+	for i := range 3 {
+		cache.Set(i, i)
+	}
+
+	mustPanic := func(name string, fn func()) {
+		t.Helper()
+
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected %s during iteration to panic", name)
+			}
+		}()
+
+		fn()
+	}
+
+	mustPanic("a promotion", func() {
+		for k := range cache.IterateAsc() {
+			cache.Get(k)
+		}
+	})
+
+	mustPanic("a removal", func() {
+		for k := range cache.IterateDesc() {
+			cache.Remove(k)
+		}
+	})
+
+	// Changing the cache right before stopping is fine
+	n := cache.Len()
+
+	for k := range cache.Iterate() {
+		cache.Remove(k)
+		break
+	}
+
+	if cache.Len() != n-1 {
+		t.Fatalf("expected %d items, got %d", n-1, cache.Len())
+	}
+}
+
+func TestStrictReset(t *testing.T) {
+	cache := NewWithOptions(2, WithStrictReset[int, int](), WithEvicted(func(int, int) {}))
+	cache.Set(1, 1)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Reset to panic")
+		}
+
+		if cache.Close(); cache.Len() != 0 {
+			t.Fatal("expected Close to clear the cache")
+		}
+	}()
+
+	cache.Reset()
+}
+
+func TestStrictResetAutoClose(t *testing.T) {
+	var closed int
+
+	cache := NewThreadSafeWithOptions(2, WithStrictReset[int, closer](), WithAutoClose[int, closer]())
+	cache.Set(1, closer{&closed})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Reset to panic")
+		}
+
+		if cache.RemoveAll(); closed != 1 {
+			t.Fatalf("expected RemoveAll to close the value, got %d closes", closed)
+		}
+	}()
+
+	cache.Reset()
+}
+
+func TestPromoteOn(t *testing.T) {
+	for _, cache := range []LRU[int, int]{
+		NewWithOptions(2, WithPromoteOn[int, int](PromoteOnSet)),
+		NewThreadSafeWithOptions(2, WithPromoteOn[int, int](PromoteOnSet)),
+	} {
+		cache.Set(1, 1)
+		cache.Set(2, 2)
+		cache.Get(1)
+		cache.Set(3, 3)
+
+		if cache.Has(1) || !cache.Has(2) {
+			t.Fatal("expected gets to not promote")
+		}
+
+		cache.Upsert(2, 20)
+		cache.Set(4, 4)
+
+		if !cache.Has(2) || cache.Has(3) {
+			t.Fatal("expected writes to promote")
+		}
+	}
+
+	cache := NewWithOptions(2, WithPromoteOn[int, int](PromoteOnGet))
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Upsert(1, 10)
+	cache.Set(3, 3)
+
+	if cache.Has(1) || !cache.Has(2) {
+		t.Fatal("expected writes to not promote")
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	fifo := NewWithOptions(2, WithPolicy[int, int](PolicyFIFO))
+	mru := NewWithOptions(2, WithPolicy[int, int](PolicyMRU))
+
+	for _, cache := range []LRU[int, int]{fifo, mru} {
+		cache.Set(1, 1)
+		cache.Set(2, 2)
+		cache.Get(1)
+		cache.Set(3, 3)
+	}
+
+	if fifo.Has(1) || !fifo.Has(2) {
+		t.Fatal("expected FIFO to evict the first inserted item")
+	}
+
+	if mru.Has(1) || !mru.Has(2) || !mru.Has(3) {
+		t.Fatal("expected MRU to evict the most recently used item")
+	}
+}
@@ -0,0 +1,164 @@
+// Package lruevents exports cache events in batches, e.g. as JSON lines to a file or as JSON
+// arrays posted to an HTTP endpoint.
+package lruevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/webmafia/lru"
+)
+
+// Sink receives a batch of events. The batch is reused after the sink returns.
+type Sink[K comparable] func(batch []lru.Event[K]) error
+
+// Exporter batches events and hands them to a sink when a batch is full, and at least once per
+// interval. Use Publish with lru.WithEvents.
+type Exporter[K comparable] struct {
+	ch       chan lru.Event[K]
+	sink     Sink[K]
+	size     int
+	interval time.Duration
+	dropped  atomic.Uint64
+	done     chan struct{}
+	once     sync.Once
+	err      error
+}
+
+// Start exporting events to a sink, in batches of at most batchSize events. An interval of zero or
+// less only hands over full batches, and the remaining events on Close.
+func New[K comparable](sink Sink[K], batchSize int, interval time.Duration) *Exporter[K] {
+	e := &Exporter[K]{
+		ch:       make(chan lru.Event[K], batchSize*4),
+		sink:     sink,
+		size:     batchSize,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+
+	go e.run()
+
+	return e
+}
+
+// Publish an event without blocking. If the exporter falls behind, the event is dropped.
+func (e *Exporter[K]) Publish(ev lru.Event[K]) {
+	select {
+	case e.ch <- ev:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// Number of events dropped because the exporter fell behind.
+func (e *Exporter[K]) Dropped() uint64 {
+	return e.dropped.Load()
+}
+
+// Flush any remaining events and stop the exporter. Returns the last error of the sink, if any.
+// Events must not be published after Close.
+func (e *Exporter[K]) Close() error {
+	e.once.Do(func() {
+		close(e.ch)
+	})
+
+	<-e.done
+	return e.err
+}
+
+func (e *Exporter[K]) run() {
+	defer close(e.done)
+
+	var tick <-chan time.Time
+
+	if e.interval > 0 {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	batch := make([]lru.Event[K], 0, e.size)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := e.sink(batch); err != nil {
+			e.err = err
+		}
+
+		clear(batch)
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case ev, ok := <-e.ch:
+			if !ok {
+				flush()
+				return
+			}
+
+			if batch = append(batch, ev); len(batch) >= e.size {
+				flush()
+			}
+
+		case <-tick:
+			flush()
+		}
+	}
+}
+
+// Write each event as a line of JSON.
+func JSONLines[K comparable](w io.Writer) Sink[K] {
+	enc := json.NewEncoder(w)
+
+	return func(batch []lru.Event[K]) error {
+		for i := range batch {
+			if err := enc.Encode(batch[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// POST each batch as a JSON array to an URL. A nil client uses http.DefaultClient.
+func HTTP[K comparable](client *http.Client, url string) Sink[K] {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var buf bytes.Buffer
+
+	return func(batch []lru.Event[K]) (err error) {
+		buf.Reset()
+
+		if err = json.NewEncoder(&buf).Encode(batch); err != nil {
+			return
+		}
+
+		resp, err := client.Post(url, "application/json", &buf)
+
+		if err != nil {
+			return
+		}
+
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("lruevents: unexpected status %s", resp.Status)
+		}
+
+		return
+	}
+}
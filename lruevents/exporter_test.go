@@ -0,0 +1,85 @@
+package lruevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/webmafia/lru"
+)
+
+func TestJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+
+	exp := New(JSONLines[string](&buf), 2, time.Hour)
+	cache := lru.NewWithOptions(1, lru.WithEvents[string, int](exp.Publish))
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	if err := exp.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 events, got %q", lines)
+	}
+
+	var e lru.Event[string]
+
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil {
+		t.Fatal(err)
+	}
+
+	if e.Kind != lru.EventEvict || e.Key != "a" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+}
+
+func TestNoInterval(t *testing.T) {
+	var buf bytes.Buffer
+
+	exp := New(JSONLines[string](&buf), 2, 0)
+	exp.Publish(lru.Event[string]{Kind: lru.EventEvict, Key: "a"})
+
+	if err := exp.Close(); err != nil || strings.Count(buf.String(), "\n") != 1 {
+		t.Fatalf("expected the event to be exported on close, got %q", buf.String())
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	var batches [][]lru.Event[int]
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []lru.Event[int]
+		body, _ := io.ReadAll(r.Body)
+
+		if err := json.Unmarshal(body, &batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		batches = append(batches, batch)
+	}))
+	defer srv.Close()
+
+	exp := New(HTTP[int](srv.Client(), srv.URL), 10, time.Hour)
+
+	for i := range 3 {
+		exp.Publish(lru.Event[int]{Kind: lru.EventInsert, Key: i})
+	}
+
+	if err := exp.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batches) != 1 || len(batches[0]) != 3 {
+		t.Fatalf("expected a single batch of 3 events, got %v", batches)
+	}
+}
@@ -0,0 +1,133 @@
+package lruhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/webmafia/lru"
+)
+
+// Settings of a DebugHandler.
+type DebugConfig[K comparable] struct {
+	TopN       int  // Number of hottest keys to list, or 10 if zero
+	ShowValues bool // Include values, which are otherwise redacted
+
+	// Parse the key query parameter of a DELETE request. Deletion is disabled if nil.
+	ParseKey func(string) (K, error)
+}
+
+type debugStats struct {
+	Len       int     `json:"len"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRatio  float64 `json:"hitRatio"`
+}
+
+type debugKey[K comparable] struct {
+	Key   K      `json:"key"`
+	Hits  uint64 `json:"hits"`
+	Bytes int    `json:"bytes,omitempty"`
+}
+
+type debugEntry[K comparable, V any] struct {
+	Key      K          `json:"key"`
+	Value    *V         `json:"value,omitempty"`
+	Created  *time.Time `json:"created,omitempty"`
+	Accessed *time.Time `json:"accessed,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	Hits     uint64     `json:"hits"`
+}
+
+type debugResponse[K comparable, V any] struct {
+	Stats   debugStats         `json:"stats"`
+	Hottest []debugKey[K]      `json:"hottest"`
+	Entries []debugEntry[K, V] `json:"entries"`
+}
+
+// DebugHandler serves the stats, hottest keys and entries of a cache as JSON on GET, with entries
+// from least to most recently used. A DELETE with a key query parameter removes the item, if
+// enabled by ParseKey.
+func DebugHandler[K comparable, V any](cache lru.LRU[K, V], cfg DebugConfig[K]) http.Handler {
+	if cfg.TopN <= 0 {
+		cfg.TopN = 10
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			serveDebug(w, cache, &cfg)
+
+		case http.MethodDelete:
+			if cfg.ParseKey == nil {
+				http.Error(w, "deletion is disabled", http.StatusMethodNotAllowed)
+				return
+			}
+
+			key, err := cfg.ParseKey(r.URL.Query().Get("key"))
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if !cache.Remove(key) {
+				http.Error(w, "key not found", http.StatusNotFound)
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			w.Header().Set("Allow", "GET, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func serveDebug[K comparable, V any](w http.ResponseWriter, cache lru.LRU[K, V], cfg *DebugConfig[K]) {
+	stats := cache.Stats()
+	res := debugResponse[K, V]{
+		Stats: debugStats{
+			Len:       cache.Len(),
+			Hits:      stats.Hits,
+			Misses:    stats.Misses,
+			Evictions: stats.Evictions,
+			HitRatio:  stats.HitRatio(),
+		},
+		Hottest: []debugKey[K]{},
+		Entries: []debugEntry[K, V]{},
+	}
+
+	for e := range cache.IterateEntries() {
+		entry := debugEntry[K, V]{
+			Key:      e.Key,
+			Created:  optionalTime(e.Created),
+			Accessed: optionalTime(e.Accessed),
+			Expires:  optionalTime(e.Expires),
+			Hits:     e.Hits,
+		}
+
+		if cfg.ShowValues {
+			entry.Value = &e.Value
+		}
+
+		res.Entries = append(res.Entries, entry)
+	}
+
+	for _, s := range cache.TopKeys(cfg.TopN) {
+		res.Hottest = append(res.Hottest, debugKey[K]{Key: s.Key, Hits: s.Hits, Bytes: s.Bytes})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
@@ -0,0 +1,51 @@
+package lruhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/webmafia/lru"
+)
+
+func TestDebugHandler(t *testing.T) {
+	cache := lru.NewThreadSafe[string, int](8)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a")
+
+	handler := DebugHandler(cache, DebugConfig[string]{
+		TopN:     1,
+		ParseKey: func(s string) (string, error) { return s, nil },
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var res struct {
+		Stats   struct{ Len, Hits int }
+		Hottest []struct{ Key string }
+		Entries []map[string]any
+	}
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Stats.Len != 2 || res.Stats.Hits != 1 || len(res.Hottest) != 1 || res.Hottest[0].Key != "a" {
+		t.Fatalf("unexpected response: %s", rec.Body.String())
+	}
+
+	if len(res.Entries) != 2 || res.Entries[0]["key"] != "b" || strings.Contains(rec.Body.String(), `"value"`) {
+		t.Fatalf("expected redacted entries in recency order, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/?key=a", nil))
+
+	if rec.Code != http.StatusNoContent || cache.Has("a") {
+		t.Fatalf("expected the key to be deleted, got status %d", rec.Code)
+	}
+}
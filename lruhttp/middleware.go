@@ -0,0 +1,204 @@
+// Package lruhttp caches HTTP responses in an LRU cache, and serves cache contents for debugging.
+package lruhttp
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/webmafia/lru"
+)
+
+// Largest response body that is cached. Larger responses pass through uncached.
+const MaxBodySize = 1 << 20
+
+// CachedResponse is a complete response, as stored in the cache.
+type CachedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+
+	// Request headers of a response's Vary, for an entry that only points to the variants of a
+	// key, which are cached under keys of their own.
+	Vary []string
+}
+
+// Middleware caches successful GET responses for ttl, keyed by keyFn or by the request URL if
+// keyFn is nil, and by the request headers of the response's Vary. A max-age in the response's
+// Cache-Control overrides the ttl. Responses with no-store, no-cache, private, Set-Cookie or a Vary
+// of *, or with a body above MaxBodySize, aren't cached. Requests with no-cache or no-store skip
+// the cache.
+func Middleware(cache lru.LRU[string, CachedResponse], keyFn func(*http.Request) string, ttl time.Duration) func(http.Handler) http.Handler {
+	if keyFn == nil {
+		keyFn = func(r *http.Request) string {
+			return r.URL.String()
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := keyFn(r)
+			reqCC := parseCacheControl(r.Header.Get("Cache-Control"))
+			_, noCache := reqCC["no-cache"]
+			_, noStore := reqCC["no-store"]
+
+			if !noCache && !noStore {
+				res, ok := cache.Get(key)
+
+				if ok && res.Vary != nil {
+					res, ok = cache.Get(varyKey(key, res.Vary, r))
+				}
+
+				if ok {
+					res.write(w)
+					return
+				}
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			if noStore || rec.status != http.StatusOK || rec.tooLarge {
+				return
+			}
+
+			resTTL, ok := responseTTL(w.Header(), ttl)
+
+			if !ok {
+				return
+			}
+
+			if vary := varyHeaders(w.Header()); vary != nil {
+				cache.SetWithTTL(key, CachedResponse{Vary: vary}, resTTL)
+				key = varyKey(key, vary, r)
+			}
+
+			cache.SetWithTTL(key, CachedResponse{
+				Status: rec.status,
+				Header: w.Header().Clone(),
+				Body:   rec.body.Bytes(),
+			}, resTTL)
+		})
+	}
+}
+
+func (res *CachedResponse) write(w http.ResponseWriter) {
+	header := w.Header()
+
+	for k, v := range res.Header {
+		header[k] = append([]string(nil), v...)
+	}
+
+	w.WriteHeader(res.Status)
+	w.Write(res.Body)
+}
+
+// The ttl of a response, and whether it may be cached at all.
+func responseTTL(header http.Header, ttl time.Duration) (time.Duration, bool) {
+	if _, ok := header["Set-Cookie"]; ok {
+		return 0, false
+	}
+
+	cc := parseCacheControl(header.Get("Cache-Control"))
+
+	for _, name := range []string{"no-store", "no-cache", "private"} {
+		if _, ok := cc[name]; ok {
+			return 0, false
+		}
+	}
+
+	if slices.Contains(varyHeaders(header), "*") {
+		return 0, false
+	}
+
+	if v, ok := cc["max-age"]; ok {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second, secs > 0
+		}
+	}
+
+	return ttl, true
+}
+
+// Canonical names of the request headers in a response's Vary, if any.
+func varyHeaders(header http.Header) (names []string) {
+	for _, v := range header.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	return
+}
+
+// Key of the variant of a response that matches the request's headers.
+func varyKey(key string, vary []string, r *http.Request) string {
+	var b strings.Builder
+
+	b.WriteString(key)
+
+	for _, name := range vary {
+		b.WriteString("\x00")
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(r.Header.Values(name), ","))
+	}
+
+	return b.String()
+}
+
+// Parse the directives of a Cache-Control header into their lowercase names and values.
+func parseCacheControl(header string) map[string]string {
+	if header == "" {
+		return nil
+	}
+
+	directives := make(map[string]string)
+
+	for _, part := range strings.Split(header, ",") {
+		name, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+		directives[strings.ToLower(name)] = strings.Trim(val, `"`)
+	}
+
+	return directives
+}
+
+// Records the status and body, while passing them through. A body above MaxBodySize is dropped.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+	tooLarge    bool
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+
+	if r.tooLarge = r.tooLarge || r.body.Len()+len(b) > MaxBodySize; r.tooLarge {
+		r.body = bytes.Buffer{}
+	} else {
+		r.body.Write(b)
+	}
+
+	return r.ResponseWriter.Write(b)
+}
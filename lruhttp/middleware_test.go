@@ -0,0 +1,126 @@
+package lruhttp
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/webmafia/lru"
+)
+
+func TestMiddleware(t *testing.T) {
+	calls := 0
+	handler := Middleware(lru.New[string, CachedResponse](8), nil, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if r.URL.Path == "/private" {
+			w.Header().Set("Cache-Control", "private")
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintf(w, "call %d", calls)
+	}))
+
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+
+		if len(header) > 0 {
+			req.Header.Set("Cache-Control", header[0])
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	get("/a")
+
+	if rec := get("/a"); rec.Body.String() != "call 1" || rec.Header().Get("Content-Type") != "text/plain" {
+		t.Fatalf("expected a cached response, got %q", rec.Body.String())
+	}
+
+	if rec := get("/a", "no-cache"); rec.Body.String() != "call 2" {
+		t.Fatalf("expected no-cache to skip the cache, got %q", rec.Body.String())
+	}
+
+	get("/private")
+
+	if rec := get("/private"); rec.Body.String() != "call 4" {
+		t.Fatalf("expected a private response to not be cached, got %q", rec.Body.String())
+	}
+}
+
+func TestMiddlewareUncacheable(t *testing.T) {
+	calls := 0
+	handler := Middleware(lru.New[string, CachedResponse](8), nil, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		switch r.URL.Path {
+		case "/cookie":
+			w.Header().Set("Set-Cookie", "session=secret")
+		case "/vary":
+			w.Header().Set("Vary", "*")
+		case "/no-cache":
+			w.Header().Set("Cache-Control", "no-cache")
+		case "/large":
+			w.Write(bytes.Repeat([]byte{'a'}, MaxBodySize))
+		}
+
+		fmt.Fprintf(w, "call %d", calls)
+	}))
+
+	for _, path := range []string{"/cookie", "/vary", "/no-cache", "/large"} {
+		before := calls
+
+		for range 2 {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			if !strings.HasSuffix(rec.Body.String(), fmt.Sprintf("call %d", calls)) {
+				t.Fatalf("expected %s to pass through, got %q", path, rec.Body.String()[max(rec.Body.Len()-10, 0):])
+			}
+		}
+
+		if calls != before+2 {
+			t.Fatalf("expected %s to not be cached", path)
+		}
+	}
+}
+
+func TestMiddlewareVary(t *testing.T) {
+	calls := 0
+	handler := Middleware(lru.New[string, CachedResponse](8), nil, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Vary", "Accept-Encoding, accept-language")
+		fmt.Fprintf(w, "%s %s %d", r.Header.Get("Accept-Encoding"), r.Header.Get("Accept-Language"), calls)
+	}))
+
+	get := func(encoding, language string) string {
+		req := httptest.NewRequest(http.MethodGet, "/a", nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		req.Header.Set("Accept-Language", language)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	get("gzip", "en")
+	get("br", "en")
+
+	if body := get("gzip", "en"); body != "gzip en 1" {
+		t.Fatalf("expected the matching variant, got %q", body)
+	}
+
+	if body := get("gzip", "sv"); body != "gzip sv 3" {
+		t.Fatalf("expected another language to be a variant of its own, got %q", body)
+	}
+
+	if body := get("br", "en"); body != "br en 2" || calls != 3 {
+		t.Fatalf("expected each variant to be cached, got %q", body)
+	}
+}
@@ -0,0 +1,337 @@
+// Package lrummap is a persistent LRU cache of byte slices, whose values live in a memory-mapped
+// file. The cache survives restarts without reloading its values, and can exceed the available
+// memory, as only keys and value offsets are held in memory.
+package lrummap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/webmafia/lru"
+)
+
+const (
+	indexMagic   = "LRUI"
+	indexVersion = 1
+)
+
+// Cache is an LRU cache of byte slices in a memory-mapped file of a fixed size, bounded by the
+// total size of its values. The recency order and value offsets are kept in a separate index
+// file, which is written by Sync and Close. Not thread-safe.
+type Cache struct {
+	path  string
+	file  *os.File
+	data  []byte
+	index lru.LRU[string, *span]
+	end   int // Offset after the last value
+	used  int // Bytes of live values, which is less than end if there are holes
+}
+
+// Location of a value in the file.
+type span struct {
+	off int
+	len int
+	sum uint32 // Checksum of the value, to detect values lost in a crash
+}
+
+// Open or create a cache in a file of size bytes, and its index in the same path with an ".idx"
+// suffix. A missing or unreadable index opens an empty cache. Items of an index that no longer
+// fit the file are dropped.
+func Open(path string, size int) (c *Cache, err error) {
+	if size <= 0 {
+		return nil, errors.New("lrummap: size must be positive")
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+
+	if err != nil {
+		return
+	}
+
+	if err = f.Truncate(int64(size)); err != nil {
+		f.Close()
+		return
+	}
+
+	data, err := mapFile(f, size)
+
+	if err != nil {
+		f.Close()
+		return
+	}
+
+	c = &Cache{
+		path: path,
+		file: f,
+		data: data,
+	}
+
+	c.index = lru.NewWithOptions(0, lru.WithUnbounded[string, *span](), lru.WithEvicted(c.free))
+
+	if err := c.readIndex(); err != nil {
+		c.index.Reset()
+		c.end, c.used = 0, 0
+	}
+
+	return c, nil
+}
+
+// Number of items.
+func (c *Cache) Len() int {
+	return c.index.Len()
+}
+
+// Total size of all values, in bytes.
+func (c *Cache) Size() int {
+	return c.used
+}
+
+// Maximum total size of all values, in bytes.
+func (c *Cache) MaxBytes() int {
+	return len(c.data)
+}
+
+func (c *Cache) Has(key string) bool {
+	return c.index.Has(key)
+}
+
+// Get a copy of a value, and mark it as most recently used. A value that fails its checksum,
+// e.g. after a crash, is removed and reported as missing.
+func (c *Cache) Get(key string) (val []byte, ok bool) {
+	return c.GetAppend(nil, key)
+}
+
+// Append a value to dst, and return the extended slice. Avoids an allocation when dst has room.
+func (c *Cache) GetAppend(dst []byte, key string) (val []byte, ok bool) {
+	s, ok := c.index.Get(key)
+
+	if !ok {
+		return dst, false
+	}
+
+	val = c.value(s)
+
+	if crc32.ChecksumIEEE(val) != s.sum {
+		c.index.Remove(key)
+		return dst, false
+	}
+
+	return append(dst, val...), true
+}
+
+// Add a copy of a value only if the key doesn't exist. Values larger than MaxBytes are
+// rejected.
+func (c *Cache) Set(key string, val []byte) (ok bool) {
+	if c.Has(key) {
+		return
+	}
+
+	return c.append(key, val)
+}
+
+// Add or overwrite a copy of a value. Values larger than MaxBytes are rejected, and any existing
+// item with the key is removed.
+func (c *Cache) Replace(key string, val []byte) (existed bool) {
+	existed = c.index.Remove(key)
+	c.append(key, val)
+
+	return
+}
+
+func (c *Cache) Remove(key string) (existed bool) {
+	return c.index.Remove(key)
+}
+
+// Clear cache.
+func (c *Cache) Reset() {
+	c.index.RemoveAll()
+	c.end = 0
+}
+
+// Write the index, after flushing the values to disk, so that the cache can be reopened.
+func (c *Cache) Sync() (err error) {
+	if err = c.file.Sync(); err != nil {
+		return
+	}
+
+	return c.writeIndex()
+}
+
+// Sync and close the cache, which must not be used afterwards.
+func (c *Cache) Close() error {
+	err := c.Sync()
+
+	if uerr := unmapFile(c.data); err == nil {
+		err = uerr
+	}
+
+	if cerr := c.file.Close(); err == nil {
+		err = cerr
+	}
+
+	c.data = nil
+	return err
+}
+
+func (c *Cache) value(s *span) []byte {
+	return c.data[s.off : s.off+s.len : s.off+s.len]
+}
+
+func (c *Cache) append(key string, val []byte) (ok bool) {
+	n := len(val)
+
+	if n > len(c.data) {
+		return false
+	}
+
+	for c.used+n > len(c.data) {
+		c.index.RemoveOldest()
+	}
+
+	if c.end+n > len(c.data) {
+		c.compact()
+	}
+
+	copy(c.data[c.end:], val)
+	c.index.Set(key, &span{off: c.end, len: n, sum: crc32.ChecksumIEEE(val)})
+	c.end += n
+	c.used += n
+
+	return true
+}
+
+func (c *Cache) free(_ string, s *span) {
+	if c.used -= s.len; c.used == 0 {
+		c.end = 0
+	}
+}
+
+// Move all values to the start of the file, so that all free space is at the end.
+func (c *Cache) compact() {
+	spans := c.index.Values()
+
+	slices.SortFunc(spans, func(a, b *span) int {
+		return a.off - b.off
+	})
+
+	off := 0
+
+	for _, s := range spans {
+		copy(c.data[off:], c.value(s))
+		s.off = off
+		off += s.len
+	}
+
+	c.end = off
+}
+
+// Write the index to a temporary file, and rename it over the previous index.
+func (c *Cache) writeIndex() (err error) {
+	tmp := c.path + ".idx.tmp"
+	f, err := os.Create(tmp)
+
+	if err != nil {
+		return
+	}
+
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	buf := append([]byte(indexMagic), indexVersion)
+
+	for key, s := range c.index.IterateAsc() {
+		buf = binary.AppendUvarint(buf, uint64(len(key)))
+		buf = append(buf, key...)
+		buf = binary.AppendUvarint(buf, uint64(s.off))
+		buf = binary.AppendUvarint(buf, uint64(s.len))
+		buf = binary.LittleEndian.AppendUint32(buf, s.sum)
+
+		if _, err = w.Write(buf); err != nil {
+			return
+		}
+
+		buf = buf[:0]
+	}
+
+	if _, err = w.Write(buf); err != nil {
+		return
+	}
+
+	if err = w.Flush(); err != nil {
+		return
+	}
+
+	if err = f.Sync(); err != nil {
+		return
+	}
+
+	return os.Rename(tmp, c.path+".idx")
+}
+
+// Read the index, from least to most recently used.
+func (c *Cache) readIndex() (err error) {
+	f, err := os.Open(c.path + ".idx")
+
+	if err != nil {
+		return
+	}
+
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	head := make([]byte, len(indexMagic)+1)
+
+	if _, err = io.ReadFull(r, head); err != nil {
+		return
+	}
+
+	if string(head[:len(indexMagic)]) != indexMagic || head[len(indexMagic)] != indexVersion {
+		return errors.New("lrummap: unsupported index")
+	}
+
+	for {
+		var (
+			n, off, size uint64
+			sum          [4]byte
+		)
+
+		if n, err = binary.ReadUvarint(r); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return
+		}
+
+		key := make([]byte, n)
+
+		if _, err = io.ReadFull(r, key); err != nil {
+			return
+		}
+
+		if off, err = binary.ReadUvarint(r); err != nil {
+			return
+		}
+
+		if size, err = binary.ReadUvarint(r); err != nil {
+			return
+		}
+
+		if _, err = io.ReadFull(r, sum[:]); err != nil {
+			return
+		}
+
+		if off+size > uint64(len(c.data)) {
+			continue
+		}
+
+		s := &span{off: int(off), len: int(size), sum: binary.LittleEndian.Uint32(sum[:])}
+		c.index.Replace(string(key), s)
+		c.end = max(c.end, s.off+s.len)
+		c.used += s.len
+	}
+}
@@ -0,0 +1,68 @@
+//go:build unix
+
+package lrummap
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+	c, err := Open(path, 56)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 10 {
+		c.Set(fmt.Sprint(i), fmt.Appendf(nil, "value %d", i))
+	}
+
+	if c.Len() != 8 || c.Has("0") || c.Has("1") {
+		t.Fatalf("expected the oldest values to be evicted by size, got %d items", c.Len())
+	}
+
+	c.Get("2")
+	c.Remove("3")
+
+	if err = c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if c, err = Open(path, 56); err != nil {
+		t.Fatal(err)
+	}
+
+	defer c.Close()
+
+	if v, ok := c.Get("9"); !ok || string(v) != "value 9" || c.Len() != 7 || c.Size() != 49 {
+		t.Fatalf("expected the items to survive a reopen, got %q and %d items", v, c.Len())
+	}
+
+	// The item read before closing is now the second most recently used
+	c.Set("a", []byte("value a"))
+	c.Set("b", []byte("value b"))
+
+	if !c.Has("2") || c.Has("4") {
+		t.Fatal("expected the recency order to survive a reopen")
+	}
+}
+
+func TestCorruptValue(t *testing.T) {
+	c, err := Open(filepath.Join(t.TempDir(), "cache"), 64)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer c.Close()
+
+	c.Set("a", []byte("value"))
+	c.data[0] ^= 0xff
+
+	if _, ok := c.Get("a"); ok || c.Has("a") {
+		t.Fatal("expected a corrupt value to be removed")
+	}
+}
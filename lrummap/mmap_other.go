@@ -0,0 +1,16 @@
+//go:build !unix
+
+package lrummap
+
+import (
+	"errors"
+	"os"
+)
+
+func mapFile(*os.File, int) ([]byte, error) {
+	return nil, errors.New("lrummap: memory mapping is unsupported on this platform")
+}
+
+func unmapFile([]byte) error {
+	return nil
+}
@@ -0,0 +1,166 @@
+// Package lruotel traces cache reads and loads with OpenTelemetry, so that cache behavior shows up
+// in distributed traces. It's a separate module, to keep the cache free of dependencies.
+package lruotel
+
+import (
+	"context"
+	"hash/maphash"
+	"time"
+
+	"github.com/webmafia/lru"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Name of the tracer, as reported to the tracer provider.
+const tracerName = "github.com/webmafia/lru/lruotel"
+
+// Attributes of the spans.
+const (
+	KeyHash    = attribute.Key("lru.key.hash")         // Hash of the key within this process, as keys may be sensitive
+	Hit        = attribute.Key("lru.hit")              // Whether the value was cached
+	LoadTime   = attribute.Key("lru.load.duration_ms") // Duration of the load, on a miss
+	Evictions  = attribute.Key("lru.evictions")        // Evictions due to capacity during the call
+	cacheLabel = attribute.Key("lru.cache")
+)
+
+// Option configures a traced cache on creation.
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+	name     string
+}
+
+// Use a tracer provider other than the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// Name the cache in an attribute of each span, to tell caches apart.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// Cache traces the reads of a cache.
+type Cache[K comparable, V any] struct {
+	cache  lru.LRU[K, V]
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+	seed   maphash.Seed
+}
+
+// Trace the reads of a cache. The cache itself is left untouched, so that untraced use still works.
+func New[K comparable, V any](cache lru.LRU[K, V], opts ...Option) *Cache[K, V] {
+	cfg := config{provider: otel.GetTracerProvider()}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	c := &Cache[K, V]{
+		cache:  cache,
+		tracer: cfg.provider.Tracer(tracerName),
+		seed:   maphash.MakeSeed(),
+	}
+
+	if cfg.name != "" {
+		c.attrs = []attribute.KeyValue{cacheLabel.String(cfg.name)}
+	}
+
+	return c
+}
+
+// Underlying cache.
+func (c *Cache[K, V]) Cache() lru.LRU[K, V] {
+	return c.cache
+}
+
+// Same as the GetOrSet of the cache, in a span that records whether the value was cached. A miss
+// runs the setter in a child span, which receives the context of that span.
+func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func(context.Context, K) (V, error)) (val V, err error) {
+	ctx, span := c.start(ctx, "lru.GetOrSet", key)
+	defer span.End()
+
+	hit := true
+	evictions := c.cache.Stats().Evictions
+
+	val, err = c.cache.GetOrSet(key, func(key K) (V, error) {
+		hit = false
+		return c.load(ctx, key, setter)
+	})
+
+	c.finish(span, hit, evictions, err)
+	return
+}
+
+// Read-through cache of NewGetter, whose reads run in a span that records whether the value was
+// cached, and whose loads run in a child span.
+func (c *Cache[K, V]) Getter(loader lru.Getter[K, V], opts ...lru.GetterOption) lru.Getter[K, V] {
+	return &getter[K, V]{
+		c: c,
+		inner: lru.NewGetter(c.cache, lru.GetterFunc[K, V](func(ctx context.Context, key K) (V, error) {
+			return c.load(ctx, key, loader.Get)
+		}), opts...),
+	}
+}
+
+type getter[K comparable, V any] struct {
+	c     *Cache[K, V]
+	inner lru.Getter[K, V]
+}
+
+// Get implements lru.Getter.
+func (g *getter[K, V]) Get(ctx context.Context, key K) (val V, err error) {
+	ctx, span := g.c.start(ctx, "lru.Get", key)
+	defer span.End()
+
+	// Callers sharing a load also miss, so check before rather than by whether this caller loads
+	hit := g.c.cache.Has(key)
+	evictions := g.c.cache.Stats().Evictions
+
+	val, err = g.inner.Get(ctx, key)
+	g.c.finish(span, hit, evictions, err)
+	return
+}
+
+func (c *Cache[K, V]) start(ctx context.Context, name string, key K) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, name, trace.WithAttributes(c.attrs...), trace.WithAttributes(
+		KeyHash.Int64(int64(maphash.Comparable(c.seed, key))),
+	))
+}
+
+func (c *Cache[K, V]) finish(span trace.Span, hit bool, evictions uint64, err error) {
+	span.SetAttributes(
+		Hit.Bool(hit),
+		Evictions.Int64(int64(c.cache.Stats().Evictions-evictions)),
+	)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// Run a load in a child span.
+func (c *Cache[K, V]) load(ctx context.Context, key K, loader func(context.Context, K) (V, error)) (val V, err error) {
+	ctx, span := c.start(ctx, "lru.load", key)
+	defer span.End()
+
+	start := time.Now()
+	val, err = loader(ctx, key)
+	span.SetAttributes(LoadTime.Float64(float64(time.Since(start)) / float64(time.Millisecond)))
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return
+}
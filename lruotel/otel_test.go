@@ -0,0 +1,78 @@
+package lruotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/webmafia/lru"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGetOrSet(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	cache := New(lru.NewThreadSafe[string, int](1), WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), WithName("test"))
+	setter := func(context.Context, string) (int, error) { return 1, nil }
+
+	cache.GetOrSet(context.Background(), "a", setter)
+	cache.GetOrSet(context.Background(), "a", setter)
+	cache.GetOrSet(context.Background(), "b", setter)
+
+	spans := recorder.Ended()
+
+	if len(spans) != 5 {
+		t.Fatalf("expected 3 reads and 2 loads, got %d spans", len(spans))
+	}
+
+	if spans[0].Name() != "lru.load" || spans[1].Name() != "lru.GetOrSet" || spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Fatal("expected the load to be a child of the read")
+	}
+
+	for i, want := range map[int]bool{1: false, 2: true, 4: false} {
+		if hit := attr(spans[i].Attributes(), Hit); hit.AsBool() != want {
+			t.Fatalf("expected span %d to have a hit of %v", i, want)
+		}
+	}
+
+	if n := attr(spans[4].Attributes(), Evictions).AsInt64(); n != 1 {
+		t.Fatalf("expected an eviction, got %d", n)
+	}
+
+	if name := attr(spans[4].Attributes(), cacheLabel).AsString(); name != "test" {
+		t.Fatalf("expected the cache name, got %q", name)
+	}
+}
+
+func TestGetter(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	cache := New(lru.NewThreadSafe[string, int](8), WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	getter := cache.Getter(lru.GetterFunc[string, int](func(context.Context, string) (int, error) { return 1, nil }))
+
+	getter.Get(context.Background(), "a")
+	getter.Get(context.Background(), "a")
+
+	spans := recorder.Ended()
+
+	if len(spans) != 3 || spans[0].Name() != "lru.load" {
+		t.Fatalf("expected 2 reads and a load, got %d spans", len(spans))
+	}
+
+	if attr(spans[0].Attributes(), LoadTime).Type() != attribute.FLOAT64 {
+		t.Fatal("expected the load duration")
+	}
+
+	if attr(spans[1].Attributes(), Hit).AsBool() || !attr(spans[2].Attributes(), Hit).AsBool() {
+		t.Fatal("expected a miss followed by a hit")
+	}
+}
+
+func attr(attrs []attribute.KeyValue, key attribute.Key) attribute.Value {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+
+	return attribute.Value{}
+}
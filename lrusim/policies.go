@@ -0,0 +1,590 @@
+package lrusim
+
+import (
+	"container/list"
+	"hash/maphash"
+)
+
+// Least recently used.
+func LRU[K comparable]() Factory[K] {
+	return Factory[K]{Name: "LRU", New: func(capacity int) Policy[K] {
+		return &lruPolicy[K]{cap: capacity, l: newRecency[K]()}
+	}}
+}
+
+type lruPolicy[K comparable] struct {
+	cap int
+	l   *recency[K]
+}
+
+func (p *lruPolicy[K]) Access(key K) bool {
+	if p.l.touch(key) {
+		return true
+	}
+
+	if p.cap > 0 {
+		if p.l.len() >= p.cap {
+			p.l.pop()
+		}
+
+		p.l.push(key)
+	}
+
+	return false
+}
+
+// First in, first out, where hits don't affect the order of eviction.
+func FIFO[K comparable]() Factory[K] {
+	return Factory[K]{Name: "FIFO", New: func(capacity int) Policy[K] {
+		return &fifoPolicy[K]{cap: capacity, l: newRecency[K]()}
+	}}
+}
+
+type fifoPolicy[K comparable] struct {
+	cap int
+	l   *recency[K]
+}
+
+func (p *fifoPolicy[K]) Access(key K) bool {
+	if p.l.contains(key) {
+		return true
+	}
+
+	if p.cap > 0 {
+		if p.l.len() >= p.cap {
+			p.l.pop()
+		}
+
+		p.l.push(key)
+	}
+
+	return false
+}
+
+// Most recently used, which suits cyclic scans larger than the cache.
+func MRU[K comparable]() Factory[K] {
+	return Factory[K]{Name: "MRU", New: func(capacity int) Policy[K] {
+		return &mruPolicy[K]{cap: capacity, l: newRecency[K]()}
+	}}
+}
+
+type mruPolicy[K comparable] struct {
+	cap int
+	l   *recency[K]
+}
+
+func (p *mruPolicy[K]) Access(key K) bool {
+	if p.l.touch(key) {
+		return true
+	}
+
+	if p.cap > 0 {
+		if p.l.len() >= p.cap {
+			p.l.remove(p.l.newest())
+		}
+
+		p.l.push(key)
+	}
+
+	return false
+}
+
+// Least frequently used, where ties are broken by recency.
+func LFU[K comparable]() Factory[K] {
+	return Factory[K]{Name: "LFU", New: func(capacity int) Policy[K] {
+		return &lfuPolicy[K]{
+			cap:     capacity,
+			freq:    make(map[K]int),
+			buckets: make(map[int]*recency[K]),
+		}
+	}}
+}
+
+type lfuPolicy[K comparable] struct {
+	cap     int
+	freq    map[K]int
+	buckets map[int]*recency[K] // Frequency -> keys
+	min     int
+}
+
+func (p *lfuPolicy[K]) Access(key K) bool {
+	if f, ok := p.freq[key]; ok {
+		p.unlink(key, f)
+		p.link(key, f+1)
+		return true
+	}
+
+	if p.cap <= 0 {
+		return false
+	}
+
+	if len(p.freq) >= p.cap {
+		victim := p.buckets[p.min].oldest()
+		p.unlink(victim, p.min)
+		delete(p.freq, victim)
+	}
+
+	p.link(key, 1)
+	p.min = 1
+	return false
+}
+
+func (p *lfuPolicy[K]) link(key K, f int) {
+	b, ok := p.buckets[f]
+
+	if !ok {
+		b = newRecency[K]()
+		p.buckets[f] = b
+	}
+
+	b.push(key)
+	p.freq[key] = f
+}
+
+func (p *lfuPolicy[K]) unlink(key K, f int) {
+	b := p.buckets[f]
+	b.remove(key)
+
+	if b.len() == 0 {
+		delete(p.buckets, f)
+
+		if p.min == f {
+			p.min++
+		}
+	}
+}
+
+// Adaptive replacement cache, which balances recency and frequency based on misses of recently
+// evicted keys.
+func ARC[K comparable]() Factory[K] {
+	return Factory[K]{Name: "ARC", New: func(capacity int) Policy[K] {
+		return &arcPolicy[K]{
+			c:  capacity,
+			t1: newRecency[K](),
+			t2: newRecency[K](),
+			b1: newRecency[K](),
+			b2: newRecency[K](),
+		}
+	}}
+}
+
+type arcPolicy[K comparable] struct {
+	c, p   int
+	t1, t2 *recency[K] // Keys seen once and more than once
+	b1, b2 *recency[K] // Keys recently evicted from t1 and t2
+}
+
+func (a *arcPolicy[K]) Access(key K) bool {
+	if a.t1.remove(key) {
+		a.t2.push(key)
+		return true
+	}
+
+	if a.t2.touch(key) {
+		return true
+	}
+
+	if a.c <= 0 {
+		return false
+	}
+
+	switch {
+	case a.b1.contains(key):
+		a.p = min(a.c, a.p+max(a.b2.len()/a.b1.len(), 1))
+		a.replace(false)
+		a.b1.remove(key)
+		a.t2.push(key)
+
+	case a.b2.contains(key):
+		a.p = max(0, a.p-max(a.b1.len()/a.b2.len(), 1))
+		a.replace(true)
+		a.b2.remove(key)
+		a.t2.push(key)
+
+	default:
+		if a.t1.len()+a.b1.len() >= a.c {
+			if a.t1.len() < a.c {
+				a.b1.pop()
+				a.replace(false)
+			} else {
+				a.t1.pop()
+			}
+		} else if total := a.t1.len() + a.t2.len() + a.b1.len() + a.b2.len(); total >= a.c {
+			if total >= 2*a.c {
+				a.b2.pop()
+			}
+
+			a.replace(false)
+		}
+
+		a.t1.push(key)
+	}
+
+	return false
+}
+
+func (a *arcPolicy[K]) replace(inB2 bool) {
+	if a.t1.len()+a.t2.len() < a.c {
+		return
+	}
+
+	if t1 := a.t1.len(); t1 > 0 && (t1 > a.p || (inB2 && t1 == a.p) || a.t2.len() == 0) {
+		a.b1.push(a.t1.pop())
+	} else {
+		a.b2.push(a.t2.pop())
+	}
+}
+
+// Full 2Q, where new keys enter a FIFO queue of a quarter of the capacity, and only keys that are
+// accessed again after leaving it enter the main LRU.
+func TwoQ[K comparable]() Factory[K] {
+	return Factory[K]{Name: "2Q", New: func(capacity int) Policy[K] {
+		return &twoQPolicy[K]{
+			c:     capacity,
+			kin:   max(1, capacity/4),
+			kout:  max(1, capacity/2),
+			am:    newRecency[K](),
+			a1in:  newRecency[K](),
+			a1out: newRecency[K](),
+		}
+	}}
+}
+
+type twoQPolicy[K comparable] struct {
+	c, kin, kout int
+	am           *recency[K] // Main LRU
+	a1in         *recency[K] // FIFO of new keys
+	a1out        *recency[K] // FIFO of keys recently evicted from a1in
+}
+
+func (q *twoQPolicy[K]) Access(key K) bool {
+	if q.am.touch(key) || q.a1in.contains(key) {
+		return true
+	}
+
+	if q.c <= 0 {
+		return false
+	}
+
+	q.reclaim()
+
+	if q.a1out.remove(key) {
+		q.am.push(key)
+	} else {
+		q.a1in.push(key)
+	}
+
+	return false
+}
+
+func (q *twoQPolicy[K]) reclaim() {
+	if q.am.len()+q.a1in.len() < q.c {
+		return
+	}
+
+	if q.a1in.len() > q.kin || q.am.len() == 0 {
+		q.a1out.push(q.a1in.pop())
+
+		if q.a1out.len() > q.kout {
+			q.a1out.pop()
+		}
+	} else {
+		q.am.pop()
+	}
+}
+
+// Window TinyLFU, where new keys enter a small LRU window and are then only admitted into the
+// main segmented LRU if they are estimated to be used more often than its victim.
+func TinyLFU[K comparable]() Factory[K] {
+	return Factory[K]{Name: "TinyLFU", New: func(capacity int) Policy[K] {
+		window := max(1, capacity/100)
+		main := max(0, capacity-window)
+
+		return &tinyLFUPolicy[K]{
+			c:            capacity,
+			windowCap:    window,
+			mainCap:      main,
+			protectedCap: main * 8 / 10,
+			window:       newRecency[K](),
+			probation:    newRecency[K](),
+			protected:    newRecency[K](),
+			sketch:       newSketch[K](capacity),
+		}
+	}}
+}
+
+type tinyLFUPolicy[K comparable] struct {
+	c, windowCap, mainCap, protectedCap int
+	window, probation, protected        *recency[K]
+	sketch                              *sketch[K]
+}
+
+func (p *tinyLFUPolicy[K]) Access(key K) bool {
+	p.sketch.increment(key)
+
+	if p.window.touch(key) || p.protected.touch(key) {
+		return true
+	}
+
+	if p.probation.remove(key) {
+		p.protected.push(key)
+
+		if p.protected.len() > p.protectedCap {
+			p.probation.push(p.protected.pop())
+		}
+
+		return true
+	}
+
+	if p.c <= 0 {
+		return false
+	}
+
+	if p.window.push(key); p.window.len() <= p.windowCap {
+		return false
+	}
+
+	candidate := p.window.pop()
+
+	switch {
+	case p.mainCap == 0:
+	case p.probation.len()+p.protected.len() < p.mainCap:
+		p.probation.push(candidate)
+
+	default:
+		if victim := p.probation.oldest(); p.sketch.estimate(candidate) > p.sketch.estimate(victim) {
+			p.probation.remove(victim)
+			p.probation.push(candidate)
+		}
+	}
+
+	return false
+}
+
+// S3-FIFO, where new keys enter a small FIFO queue, and only keys that are hit again meanwhile, or
+// that are remembered as recently evicted, enter the main FIFO queue. Keys of the main queue that
+// have been hit are reinserted rather than evicted.
+func S3FIFO[K comparable]() Factory[K] {
+	return Factory[K]{Name: "S3-FIFO", New: func(capacity int) Policy[K] {
+		small := max(capacity/10, 1)
+
+		return &s3fifoPolicy[K]{
+			cap:      capacity,
+			smallCap: small,
+			small:    newRecency[K](),
+			main:     newRecency[K](),
+			ghosts:   newRecency[K](),
+			freq:     make(map[K]uint8),
+		}
+	}}
+}
+
+type s3fifoPolicy[K comparable] struct {
+	cap      int
+	smallCap int
+	small    *recency[K]
+	main     *recency[K]
+	ghosts   *recency[K] // Keys recently evicted from the small queue
+	freq     map[K]uint8 // Hits of queued keys, up to 3
+}
+
+func (p *s3fifoPolicy[K]) Access(key K) bool {
+	if p.small.contains(key) || p.main.contains(key) {
+		p.freq[key] = min(p.freq[key]+1, 3)
+		return true
+	}
+
+	if p.cap <= 0 {
+		return false
+	}
+
+	for p.small.len()+p.main.len() >= p.cap {
+		p.evict()
+	}
+
+	if p.ghosts.remove(key) {
+		p.main.push(key)
+	} else {
+		p.small.push(key)
+	}
+
+	p.freq[key] = 0
+	return false
+}
+
+func (p *s3fifoPolicy[K]) evict() {
+	if p.small.len() >= p.smallCap || p.main.len() == 0 {
+		p.evictSmall()
+	} else {
+		p.evictMain()
+	}
+}
+
+// Move the oldest key of the small queue to the main queue if it has been hit, or else evict it.
+func (p *s3fifoPolicy[K]) evictSmall() {
+	for p.small.len() > 0 {
+		key := p.small.pop()
+
+		if p.freq[key] > 0 {
+			p.freq[key] = 0
+			p.main.push(key)
+
+			if p.small.len()+p.main.len() >= p.cap {
+				p.evictMain()
+			}
+
+			continue
+		}
+
+		delete(p.freq, key)
+
+		if p.ghosts.push(key); p.ghosts.len() > p.cap-p.smallCap {
+			p.ghosts.pop()
+		}
+
+		return
+	}
+}
+
+// Reinsert the oldest keys of the main queue that have been hit, until one hasn't, and evict it.
+func (p *s3fifoPolicy[K]) evictMain() {
+	for p.main.len() > 0 {
+		key := p.main.pop()
+
+		if f := p.freq[key]; f > 0 {
+			p.freq[key] = f - 1
+			p.main.push(key)
+			continue
+		}
+
+		delete(p.freq, key)
+		return
+	}
+}
+
+// Count-min sketch of 4-bit counters, which are halved periodically so that old accesses fade.
+type sketch[K comparable] struct {
+	seed    maphash.Seed
+	rows    [4][]uint8
+	mask    uint64
+	samples int
+	limit   int
+}
+
+func newSketch[K comparable](capacity int) *sketch[K] {
+	width := 16
+
+	for width < capacity {
+		width <<= 1
+	}
+
+	s := &sketch[K]{
+		seed:  maphash.MakeSeed(),
+		mask:  uint64(width - 1),
+		limit: 10 * max(capacity, 1),
+	}
+
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+
+	return s
+}
+
+func (s *sketch[K]) increment(key K) {
+	h := hashKey(s.seed, key)
+
+	for i := range s.rows {
+		if c := &s.rows[i][s.index(h, i)]; *c < 15 {
+			*c++
+		}
+	}
+
+	if s.samples++; s.samples >= s.limit {
+		for i := range s.rows {
+			for j := range s.rows[i] {
+				s.rows[i][j] /= 2
+			}
+		}
+
+		s.samples /= 2
+	}
+}
+
+func (s *sketch[K]) estimate(key K) (n uint8) {
+	h := hashKey(s.seed, key)
+	n = 15
+
+	for i := range s.rows {
+		n = min(n, s.rows[i][s.index(h, i)])
+	}
+
+	return
+}
+
+func (s *sketch[K]) index(h uint64, row int) uint64 {
+	return (h + uint64(row)*(h>>32|1)) & s.mask
+}
+
+// Keys in order of recency, where the front is the most recent.
+type recency[K comparable] struct {
+	items map[K]*list.Element
+	order *list.List
+}
+
+func newRecency[K comparable]() *recency[K] {
+	return &recency[K]{
+		items: make(map[K]*list.Element),
+		order: list.New(),
+	}
+}
+
+func (r *recency[K]) len() int {
+	return len(r.items)
+}
+
+func (r *recency[K]) contains(key K) bool {
+	_, ok := r.items[key]
+	return ok
+}
+
+// Move a key to the front, if it exists.
+func (r *recency[K]) touch(key K) bool {
+	e, ok := r.items[key]
+
+	if ok {
+		r.order.MoveToFront(e)
+	}
+
+	return ok
+}
+
+func (r *recency[K]) push(key K) {
+	r.items[key] = r.order.PushFront(key)
+}
+
+func (r *recency[K]) newest() K {
+	return r.order.Front().Value.(K)
+}
+
+func (r *recency[K]) oldest() K {
+	return r.order.Back().Value.(K)
+}
+
+// Remove and return the oldest key.
+func (r *recency[K]) pop() K {
+	key := r.oldest()
+	r.remove(key)
+	return key
+}
+
+func (r *recency[K]) remove(key K) bool {
+	e, ok := r.items[key]
+
+	if ok {
+		r.order.Remove(e)
+		delete(r.items, key)
+	}
+
+	return ok
+}
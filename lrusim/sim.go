@@ -0,0 +1,121 @@
+// Package lrusim replays key traces against eviction policies and capacities, and reports their
+// hit ratios, to help choose a policy and capacity.
+package lrusim
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"text/tabwriter"
+)
+
+// Policy is a simulated cache of keys. Access looks up a key, and admits it on a miss.
+type Policy[K comparable] interface {
+	Access(key K) (hit bool)
+}
+
+// Factory creates a named policy with a capacity.
+type Factory[K comparable] struct {
+	Name string
+	New  func(capacity int) Policy[K]
+}
+
+// All policies of this package.
+func Policies[K comparable]() []Factory[K] {
+	return []Factory[K]{LRU[K](), FIFO[K](), MRU[K](), LFU[K](), ARC[K](), TwoQ[K](), TinyLFU[K](), S3FIFO[K]()}
+}
+
+// Result of a policy at a capacity.
+type Result struct {
+	Policy   string
+	Capacity int
+	Hits     uint64
+	Misses   uint64
+}
+
+// Ratio of hits to all accesses, or zero if there has been no accesses.
+func (r Result) HitRatio() float64 {
+	if total := r.Hits + r.Misses; total > 0 {
+		return float64(r.Hits) / float64(total)
+	}
+
+	return 0
+}
+
+// Replay a trace once against every policy at every capacity. Results are ordered by policy, and
+// then by capacity. Without policies, all policies of this package are used.
+func Run[K comparable](trace iter.Seq[K], capacities []int, policies ...Factory[K]) []Result {
+	if len(policies) == 0 {
+		policies = Policies[K]()
+	}
+
+	results := make([]Result, 0, len(policies)*len(capacities))
+	sims := make([]Policy[K], 0, cap(results))
+
+	for _, p := range policies {
+		for _, capacity := range capacities {
+			results = append(results, Result{Policy: p.Name, Capacity: capacity})
+			sims = append(sims, p.New(capacity))
+		}
+	}
+
+	for key := range trace {
+		for i, sim := range sims {
+			if sim.Access(key) {
+				results[i].Hits++
+			} else {
+				results[i].Misses++
+			}
+		}
+	}
+
+	return results
+}
+
+// Write results as an aligned table.
+func Print(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "policy\tcapacity\thits\tmisses\thit ratio\t")
+
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.4f\t\n", r.Policy, r.Capacity, r.Hits, r.Misses, r.HitRatio())
+	}
+
+	return tw.Flush()
+}
+
+// Lines is a trace read from one key per line. Anything after a tab is ignored, so that traces of
+// lru.TraceWriter can be replayed. Empty lines are skipped.
+type Lines struct {
+	r   io.Reader
+	err error
+}
+
+// Read a trace of one key per line. The trace can only be iterated once.
+func ReadLines(r io.Reader) *Lines {
+	return &Lines{r: r}
+}
+
+// Iterate all keys. Check Err afterwards.
+func (l *Lines) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		s := bufio.NewScanner(l.r)
+
+		for s.Scan() {
+			line, _, _ := strings.Cut(s.Text(), "\t")
+
+			if line != "" && !yield(line) {
+				return
+			}
+		}
+
+		l.err = s.Err()
+	}
+}
+
+// First error encountered while reading, if any.
+func (l *Lines) Err() error {
+	return l.err
+}
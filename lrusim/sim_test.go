@@ -0,0 +1,101 @@
+package lrusim
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	results := Run(slices.Values([]string{"a", "b", "a", "b"}), []int{0, 2})
+
+	if len(results) != 2*len(Policies[string]()) {
+		t.Fatalf("expected a result per policy and capacity, got %d", len(results))
+	}
+
+	for _, r := range results {
+		want := uint64(2)
+
+		if r.Capacity == 0 {
+			want = 0
+		}
+
+		if r.Hits != want || r.Hits+r.Misses != 4 {
+			t.Errorf("%s at %d: expected %d hits, got %+v", r.Policy, r.Capacity, want, r)
+		}
+	}
+}
+
+func TestScanResistance(t *testing.T) {
+	// A hot set of 50 keys, interleaved with a scan of unique keys.
+	trace := make([]int, 0, 20000)
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	for i := range 10000 {
+		trace = append(trace, rnd.IntN(50), 1000+i)
+	}
+
+	ratios := make(map[string]float64)
+
+	for _, r := range Run(slices.Values(trace), []int{64}) {
+		ratios[r.Policy] = r.HitRatio()
+
+		if r.Hits+r.Misses != uint64(len(trace)) {
+			t.Fatalf("%s: expected %d accesses, got %+v", r.Policy, len(trace), r)
+		}
+	}
+
+	for _, name := range []string{"LFU", "ARC", "2Q", "TinyLFU", "S3-FIFO"} {
+		if ratios[name] <= ratios["LRU"] {
+			t.Errorf("expected %s to beat LRU under scans, got %v", name, ratios)
+		}
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	lines := ReadLines(strings.NewReader("a\nb\n\na\n"))
+	results := Run(lines.All(), []int{2}, LRU[string]())
+
+	if err := lines.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := results[0]; r.Hits != 1 || r.Misses != 2 {
+		t.Fatalf("expected 1 hit and 2 misses, got %+v", r)
+	}
+
+	var buf bytes.Buffer
+
+	if err := Print(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "0.3333") {
+		t.Fatalf("expected the hit ratio in the table, got %q", buf.String())
+	}
+}
+
+func TestReadLinesTrace(t *testing.T) {
+	lines := ReadLines(strings.NewReader("a\thit\t1\nb\tmiss\t2\n"))
+
+	if keys := slices.Collect(lines.All()); !slices.Equal(keys, []string{"a", "b"}) {
+		t.Fatalf("expected keys only, got %q", keys)
+	}
+}
+
+func TestCyclicScan(t *testing.T) {
+	// A loop over 100 keys, which LRU always misses with a smaller cache
+	trace := make([]int, 0, 10000)
+
+	for i := range 10000 {
+		trace = append(trace, i%100)
+	}
+
+	results := Run(slices.Values(trace), []int{50}, LRU[int](), MRU[int]())
+
+	if results[0].Hits != 0 || results[1].HitRatio() < 0.4 {
+		t.Fatalf("expected MRU to beat LRU under cyclic scans, got %+v", results)
+	}
+}
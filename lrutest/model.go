@@ -0,0 +1,259 @@
+// Package lrutest checks implementations of lru.LRU against a reference model, by applying the
+// same sequence of operations to both and comparing every result and the recency order.
+package lrutest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/webmafia/lru"
+)
+
+// Kind of operation.
+type OpKind uint8
+
+const (
+	OpGet OpKind = iota
+	OpSet
+	OpReplace
+	OpUpsert
+	OpRemove
+	OpTouch
+	OpHas
+	OpResize
+	opKinds
+)
+
+var opNames = [...]string{
+	OpGet:     "Get",
+	OpSet:     "Set",
+	OpReplace: "Replace",
+	OpUpsert:  "Upsert",
+	OpRemove:  "Remove",
+	OpTouch:   "Touch",
+	OpHas:     "Has",
+	OpResize:  "Resize",
+}
+
+func (k OpKind) String() string {
+	if k < opKinds {
+		return opNames[k]
+	}
+
+	return fmt.Sprintf("OpKind(%d)", k)
+}
+
+// An operation on a cache.
+type Op[K comparable, V any] struct {
+	Kind     OpKind
+	Key      K
+	Val      V
+	Capacity int // For OpResize
+}
+
+func (op Op[K, V]) String() string {
+	switch op.Kind {
+	case OpResize:
+		return fmt.Sprintf("Resize(%d)", op.Capacity)
+
+	case OpSet, OpReplace, OpUpsert:
+		return fmt.Sprintf("%s(%v, %v)", op.Kind, op.Key, op.Val)
+
+	default:
+		return fmt.Sprintf("%s(%v)", op.Kind, op.Key)
+	}
+}
+
+// Model is a reference LRU cache, i.e. a map of values and a list of keys from least to most
+// recently used. A capacity of zero or less rejects all items.
+type Model[K comparable, V any] struct {
+	capacity int
+	vals     map[K]V
+	order    []K
+}
+
+// Create a model with a capacity.
+func NewModel[K comparable, V any](capacity int) *Model[K, V] {
+	return &Model[K, V]{
+		capacity: capacity,
+		vals:     make(map[K]V),
+	}
+}
+
+// Apply an operation, and return its results as returned by Apply.
+func (m *Model[K, V]) Apply(op Op[K, V]) (val V, ok bool) {
+	_, exists := m.vals[op.Key]
+
+	switch op.Kind {
+	case OpGet:
+		if val, ok = m.vals[op.Key]; ok {
+			m.touch(op.Key)
+		}
+
+	case OpSet:
+		ok = !exists && m.insert(op.Key, op.Val)
+
+	case OpReplace, OpUpsert:
+		if ok = exists; exists {
+			m.vals[op.Key] = op.Val
+			m.touch(op.Key)
+		} else {
+			m.insert(op.Key, op.Val)
+		}
+
+	case OpRemove:
+		if ok = exists; exists {
+			delete(m.vals, op.Key)
+			m.order = slices.DeleteFunc(m.order, func(k K) bool { return k == op.Key })
+		}
+
+	case OpTouch:
+		if ok = exists; exists {
+			m.touch(op.Key)
+		}
+
+	case OpHas:
+		ok = exists
+
+	case OpResize:
+		m.capacity = op.Capacity
+
+		for len(m.order) > max(m.capacity, 0) {
+			m.evict()
+		}
+	}
+
+	return
+}
+
+// Keys from least to most recently used.
+func (m *Model[K, V]) Keys() []K {
+	return slices.Clone(m.order)
+}
+
+func (m *Model[K, V]) touch(key K) {
+	m.order = slices.DeleteFunc(m.order, func(k K) bool { return k == key })
+	m.order = append(m.order, key)
+}
+
+func (m *Model[K, V]) insert(key K, val V) bool {
+	if m.capacity <= 0 {
+		return false
+	}
+
+	if len(m.order) >= m.capacity {
+		m.evict()
+	}
+
+	m.vals[key] = val
+	m.order = append(m.order, key)
+	return true
+}
+
+func (m *Model[K, V]) evict() {
+	delete(m.vals, m.order[0])
+	m.order = m.order[1:]
+}
+
+// Apply an operation to a cache, and return its results. Operations that return a single bool
+// return it as ok, and Resize returns nothing.
+func Apply[K comparable, V any](cache lru.LRU[K, V], op Op[K, V]) (val V, ok bool) {
+	switch op.Kind {
+	case OpGet:
+		val, ok = cache.Get(op.Key)
+
+	case OpSet:
+		ok = cache.Set(op.Key, op.Val)
+
+	case OpReplace:
+		ok = cache.Replace(op.Key, op.Val)
+
+	case OpUpsert:
+		ok = cache.Upsert(op.Key, op.Val)
+
+	case OpRemove:
+		ok = cache.Remove(op.Key)
+
+	case OpTouch:
+		ok = cache.Touch(op.Key)
+
+	case OpHas:
+		ok = cache.Has(op.Key)
+
+	case OpResize:
+		cache.Resize(op.Capacity)
+	}
+
+	return
+}
+
+// Apply operations to an empty cache of a capacity, and to a model of the same capacity. Returns
+// an error describing the first result, length or recency order that differs.
+func Check[K comparable, V comparable](cache lru.LRU[K, V], capacity int, ops []Op[K, V]) error {
+	model := NewModel[K, V](capacity)
+
+	for i, op := range ops {
+		val, ok := Apply(cache, op)
+		wantVal, wantOk := model.Apply(op)
+
+		if val != wantVal || ok != wantOk {
+			return fmt.Errorf("op %d %s: expected (%v, %v), got (%v, %v)", i, op, wantVal, wantOk, val, ok)
+		}
+
+		if keys, want := cache.Keys(), model.Keys(); cache.Len() != len(want) || !slices.Equal(keys, want) {
+			return fmt.Errorf("op %d %s: expected keys %v, got %v (len %d)", i, op, want, keys, cache.Len())
+		}
+	}
+
+	return nil
+}
+
+// Generate n random operations on keys in [0, keys), with capacities of Resize in [0, keys].
+func RandomOps(rnd *rand.Rand, n, keys int) []Op[int, int] {
+	ops := make([]Op[int, int], n)
+
+	for i := range ops {
+		ops[i] = Op[int, int]{
+			Kind:     OpKind(rnd.IntN(int(opKinds))),
+			Key:      rnd.IntN(keys),
+			Val:      rnd.Int(),
+			Capacity: rnd.IntN(keys + 1),
+		}
+	}
+
+	return ops
+}
+
+// Decode fuzzer input into operations of 2 bytes each, on up to 16 keys.
+func Decode(data []byte) []Op[int, int] {
+	ops := make([]Op[int, int], 0, len(data)/2)
+
+	for i := 0; i+1 < len(data); i += 2 {
+		ops = append(ops, Op[int, int]{
+			Kind:     OpKind(data[i] % byte(opKinds)),
+			Key:      int(data[i+1] % 16),
+			Val:      int(data[i+1]),
+			Capacity: int(data[i+1] % 9),
+		})
+	}
+
+	return ops
+}
+
+// Check random sequences of operations against caches created by newCache, and fail on the first
+// difference from the model. The caches must have strict LRU semantics.
+func Conformance(t testing.TB, newCache func(capacity int) lru.LRU[int, int]) {
+	t.Helper()
+
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	for _, capacity := range []int{0, 1, 2, 3, 8} {
+		for range 20 {
+			if err := Check(newCache(capacity), capacity, RandomOps(rnd, 200, 12)); err != nil {
+				t.Fatalf("capacity %d: %v", capacity, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package lrutest
+
+import (
+	"testing"
+
+	"github.com/webmafia/lru"
+)
+
+var builtins = map[string]func(capacity int) lru.LRU[int, int]{
+	"New": func(capacity int) lru.LRU[int, int] {
+		return lru.New[int, int](capacity)
+	},
+	"NewThreadSafe": func(capacity int) lru.LRU[int, int] {
+		return lru.NewThreadSafe[int, int](capacity)
+	},
+}
+
+func TestConformance(t *testing.T) {
+	for name, newCache := range builtins {
+		t.Run(name, func(t *testing.T) {
+			Conformance(t, newCache)
+		})
+	}
+}
+
+func TestCheckDetectsDifferences(t *testing.T) {
+	// A cache of 3 with a model of 2 differs once a third item is added.
+	ops := []Op[int, int]{{Kind: OpSet, Key: 1}, {Kind: OpSet, Key: 2}, {Kind: OpSet, Key: 3}}
+
+	if err := Check(lru.New[int, int](3), 2, ops); err == nil {
+		t.Fatal("expected a difference")
+	}
+}
+
+func FuzzCache(f *testing.F) {
+	f.Add([]byte{byte(OpSet), 1, byte(OpSet), 2, byte(OpGet), 1, byte(OpRemove), 2, byte(OpResize), 1})
+	f.Add([]byte{byte(OpReplace), 3, byte(OpTouch), 3, byte(OpUpsert), 4, byte(OpHas), 3})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for name, newCache := range builtins {
+			if err := Check(newCache(4), 4, Decode(data)); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		}
+	})
+}
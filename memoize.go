@@ -0,0 +1,17 @@
+package lru
+
+import "context"
+
+// Cached version of a function, which remembers the results of at most capacity inputs.
+// Concurrent calls of the same input share a single call of f, as with NewGetter, and calls of
+// other inputs are never blocked by it. Results expire with WithExpireAfterWrite, and errors are
+// only remembered with WithNegativeTTL. The returned function is safe for concurrent use.
+func Memoize[In comparable, Out any](f func(In) (Out, error), capacity int, opts ...Option[In, Out]) func(In) (Out, error) {
+	getter := NewGetter(NewThreadSafeWithOptions(capacity, opts...), GetterFunc[In, Out](func(_ context.Context, in In) (Out, error) {
+		return f(in)
+	}))
+
+	return func(in In) (Out, error) {
+		return getter.Get(context.Background(), in)
+	}
+}
@@ -0,0 +1,48 @@
+package lru
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoize(t *testing.T) {
+	var calls int
+
+	clock := newFakeClock()
+	errOdd := errors.New("odd")
+
+	square := Memoize(func(n int) (int, error) {
+		calls++
+
+		if n%2 != 0 {
+			return 0, errOdd
+		}
+
+		return n * n, nil
+	}, 8, WithClock[int, int](clock), WithExpireAfterWrite[int, int](time.Minute))
+
+	for range 3 {
+		if v, err := square(4); err != nil || v != 16 {
+			t.Fatalf("expected 16, got %d and %v", v, err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected a single call, got %d", calls)
+	}
+
+	clock.Advance(time.Minute)
+	square(4)
+
+	if calls != 2 {
+		t.Fatalf("expected an expired result to be computed again, got %d calls", calls)
+	}
+
+	square(3)
+	square(3)
+
+	if _, err := square(3); !errors.Is(err, errOdd) || calls != 5 {
+		t.Fatalf("expected errors to not be remembered, got %v after %d calls", err, calls)
+	}
+}
@@ -0,0 +1,173 @@
+package lru
+
+import (
+	"math"
+	"runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Settings of a MemoryController.
+type MemoryConfig struct {
+	Fraction float64       // Target fraction of the memory limit, or 0.8 if zero or less
+	Interval time.Duration // How often memory is checked, or every second if zero
+	Min      int           // Never shrink the cache below this capacity
+	Max      int           // Never grow the cache above this capacity, which is also the initial capacity
+
+	// Also check memory after each garbage collection, to shed cold items as soon as the runtime
+	// is under pressure rather than at the next interval.
+	AfterGC bool
+
+	// Memory in use and its limit, in bytes. Defaults to the memory of the Go runtime and the
+	// GOMEMLIMIT. A limit of zero, or math.MaxInt64 as when GOMEMLIMIT is unset, skips the check.
+	Gauge func() (used, limit uint64)
+}
+
+// MemoryController resizes a cache in the background, to keep memory usage below a target
+// fraction of a memory limit. It shrinks the cache in proportion to the excess usage, and grows it
+// back by a tenth of its capacity at a time once usage is at least a tenth below the target.
+// Shrinking evicts the excess items right away, to release memory as soon as the limit is hit.
+type MemoryController struct {
+	cfg      MemoryConfig
+	resize   func(capacity int)
+	capacity atomic.Int64
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Start resizing a cache based on memory usage, until closed.
+func NewMemoryController[K comparable, V any](c LRU[K, V], cfg MemoryConfig) *MemoryController {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+
+	if cfg.Fraction <= 0 {
+		cfg.Fraction = 0.8
+	}
+
+	if cfg.Gauge == nil {
+		cfg.Gauge = runtimeMemory
+	}
+
+	m := &MemoryController{
+		cfg: cfg,
+		resize: func(capacity int) {
+			c.Resize(capacity, ResizeNow())
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	m.capacity.Store(int64(cfg.Max))
+	c.Resize(cfg.Max, ResizeNow())
+	go m.run()
+
+	return m
+}
+
+// Current capacity set by the controller.
+func (m *MemoryController) Capacity() int {
+	return int(m.capacity.Load())
+}
+
+// Stop resizing the cache. The cache keeps its current capacity.
+func (m *MemoryController) Close() error {
+	m.once.Do(func() {
+		close(m.stop)
+		<-m.done
+	})
+
+	return nil
+}
+
+func (m *MemoryController) run() {
+	ticker := time.NewTicker(m.cfg.Interval)
+
+	var gc chan struct{}
+
+	if m.cfg.AfterGC {
+		gc = make(chan struct{}, 1)
+		m.watchGC(gc)
+	}
+
+	defer func() {
+		ticker.Stop()
+		close(m.done)
+	}()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+
+		case <-ticker.C:
+			m.step()
+
+		case <-gc:
+			m.step()
+		}
+	}
+}
+
+// Adjust the capacity once, based on the current memory usage.
+func (m *MemoryController) step() {
+	used, limit := m.cfg.Gauge()
+
+	if limit == 0 || limit >= math.MaxInt64 || used == 0 {
+		return
+	}
+
+	target := m.cfg.Fraction * float64(limit)
+	current := m.Capacity()
+	capacity := current
+
+	if float64(used) > target {
+		capacity = int(float64(capacity) * target / float64(used))
+	} else if float64(used) < 0.9*target {
+		capacity += max(capacity/10, 1)
+	}
+
+	capacity = min(max(capacity, m.cfg.Min), m.cfg.Max)
+
+	if capacity != current {
+		m.capacity.Store(int64(capacity))
+		m.resize(capacity)
+	}
+}
+
+var memorySamples = []metrics.Sample{
+	{Name: "/memory/classes/total:bytes"},
+	{Name: "/memory/classes/heap/released:bytes"},
+	{Name: "/gc/gomemlimit:bytes"},
+}
+
+// Memory of the Go runtime, as accounted for by GOMEMLIMIT, and the GOMEMLIMIT itself.
+func runtimeMemory() (used, limit uint64) {
+	samples := make([]metrics.Sample, len(memorySamples))
+	copy(samples, memorySamples)
+	metrics.Read(samples)
+
+	return samples[0].Value.Uint64() - samples[1].Value.Uint64(), samples[2].Value.Uint64()
+}
+
+// Unreachable object whose cleanup signals a garbage collection. Large enough to avoid the tiny
+// allocator, whose objects might never be cleaned up.
+type gcSentinel [32]byte
+
+// Signal a garbage collection without blocking, and watch for the next one, unless stopped.
+func (m *MemoryController) signalGC(gc chan<- struct{}) {
+	select {
+	case <-m.stop:
+		return
+	default:
+	}
+
+	select {
+	case gc <- struct{}{}:
+	default:
+	}
+
+	m.watchGC(gc)
+}
@@ -0,0 +1,113 @@
+package lru
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryController(t *testing.T) {
+	var used uint64
+
+	cache := NewThreadSafe[int, int](0)
+	m := NewMemoryController(cache, MemoryConfig{
+		Fraction: 0.5,
+		Interval: time.Hour,
+		Min:      10,
+		Max:      1000,
+		Gauge: func() (uint64, uint64) {
+			return used, 1000
+		},
+	})
+	defer m.Close()
+
+	for i := range 1000 {
+		cache.Set(i, i)
+	}
+
+	used = 1000
+	m.step()
+
+	if c := m.Capacity(); c != 500 {
+		t.Fatalf("expected capacity to shrink in proportion to excess usage, got %d", c)
+	}
+
+	if n := cache.Len(); n != 500 {
+		t.Fatalf("expected the excess items to be evicted right away, got %d", n)
+	}
+
+	used = 1
+	m.step()
+
+	if c := m.Capacity(); c != 550 {
+		t.Fatalf("expected capacity to grow by a tenth, got %d", c)
+	}
+
+	used = 449
+	m.step()
+
+	if c := m.Capacity(); c != 605 {
+		t.Fatalf("expected capacity to grow below 90%% of target, got %d", c)
+	}
+
+	used = 455
+	m.step()
+
+	if c := m.Capacity(); c != 605 {
+		t.Fatalf("expected capacity to be kept near the target, got %d", c)
+	}
+}
+
+func TestMemoryControllerDefaultFraction(t *testing.T) {
+	cache := NewThreadSafe[int, int](0)
+	m := NewMemoryController(cache, MemoryConfig{
+		Interval: time.Hour,
+		Max:      1000,
+		Gauge: func() (uint64, uint64) {
+			return 790, 1000
+		},
+	})
+	defer m.Close()
+
+	if m.step(); m.Capacity() != 1000 {
+		t.Fatalf("expected usage below the default fraction to keep the capacity, got %d", m.Capacity())
+	}
+}
+
+func TestMemoryControllerAfterGC(t *testing.T) {
+	var used atomic.Uint64
+
+	cache := NewThreadSafe[int, int](0)
+	m := NewMemoryController(cache, MemoryConfig{
+		Fraction: 0.5,
+		Interval: time.Hour,
+		Max:      1000,
+		AfterGC:  true,
+		Gauge: func() (uint64, uint64) {
+			return used.Load(), 1000
+		},
+	})
+	defer m.Close()
+
+	used.Store(1000)
+
+	for deadline := time.Now().Add(5 * time.Second); cache.Cap() == 1000; {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a garbage collection to shrink the cache")
+		}
+
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+
+	if c := cache.Cap(); c != 500 {
+		t.Fatalf("expected the cache to be resized, got %d", c)
+	}
+}
+
+func TestRuntimeMemory(t *testing.T) {
+	if used, limit := runtimeMemory(); used == 0 || limit == 0 {
+		t.Fatalf("expected runtime memory metrics, got %d and %d", used, limit)
+	}
+}
@@ -0,0 +1,145 @@
+package lru
+
+import "slices"
+
+// Fold the items of another cache into this one, interleaving both recency orders by the
+// relative position of each item. An item in both caches gets the later of its two positions,
+// and the value returned by onConflict, or the other cache's value if onConflict is nil. If the
+// items exceed the capacity, the least recently used are evicted or rejected.
+func (c *lru[K, V]) Merge(other LRU[K, V], onConflict func(key K, ours, theirs V) V) {
+	if other == LRU[K, V](c) {
+		return
+	}
+
+	c.merge(slices.Collect(other.IterateEntries()), onConflict)
+}
+
+// Merge implements LRU.
+func (t *threadsafe[K, V]) Merge(other LRU[K, V], onConflict func(key K, ours, theirs V) V) {
+	if other == LRU[K, V](t) {
+		return
+	}
+
+	// Collect the other items first, as the other cache might need this lock
+	entries := slices.Collect(other.IterateEntries())
+
+	t.lock()
+	defer t.unlock()
+
+	t.lru.merge(entries, onConflict)
+}
+
+type mergedItem[K comparable, V any] struct {
+	key     K
+	val     V
+	expires int64
+	theirs  bool // Whether the item is new to this cache
+}
+
+func (c *lru[K, V]) merge(entries []EntryInfo[K, V], onConflict func(key K, ours, theirs V) V) {
+	c.PurgeExpired()
+
+	ours := slices.Collect(c.ascending())
+	n, m := len(ours), len(entries)
+
+	// Positions of both orders, to find the later position of items in both caches
+	ourPos := make(map[K]int, n)
+	theirPos := make(map[K]int, m)
+
+	for i, idx := range ours {
+		ourPos[c.keys[idx]] = i
+	}
+
+	for j := range entries {
+		entries[j].Key = c.canonical(entries[j].Key)
+		entries[j].Value = c.copy(entries[j].Value)
+		theirPos[entries[j].Key] = j
+	}
+
+	// Whether position i of n is later than position j of m
+	later := func(i, j int) bool {
+		return (i+1)*m > (j+1)*n
+	}
+
+	items := make([]mergedItem[K, V], 0, n+m)
+
+	for i, j := 0, 0; i < n || j < m; {
+		if j >= m || (i < n && !later(i, j)) {
+			idx := ours[i]
+			key := c.keys[idx]
+			i++
+
+			if jj, ok := theirPos[key]; !ok {
+				items = append(items, mergedItem[K, V]{key: key, val: c.vals[idx], expires: c.expires[idx]})
+			} else if !later(i-1, jj) {
+				continue
+			} else {
+				items = append(items, mergedItem[K, V]{key: key, val: resolveConflict(key, c.vals[idx], entries[jj].Value, onConflict), expires: c.expires[idx]})
+			}
+
+			continue
+		}
+
+		e := &entries[j]
+		j++
+
+		item := mergedItem[K, V]{key: e.Key, val: e.Value, expires: e.Expires.UnixNano(), theirs: true}
+
+		if e.Expires.IsZero() {
+			item.expires = 0
+		}
+
+		if ii, ok := ourPos[e.Key]; ok {
+			if later(ii, j-1) {
+				continue
+			}
+
+			item.val = resolveConflict(e.Key, c.vals[ours[ii]], e.Value, onConflict)
+			item.theirs = false
+		}
+
+		items = append(items, item)
+	}
+
+	// Make room by dropping the least recently used items, except pinned ones
+	excess := len(items) - c.limit()
+	kept := items[:0]
+
+	for _, item := range items {
+		if excess > 0 {
+			if item.theirs {
+				c.reject(item.key, item.val)
+				excess--
+				continue
+			}
+
+			if idx, _ := c.index(item.key); !c.pinned[idx] {
+				c.remove(idx)
+				excess--
+				continue
+			}
+		}
+
+		kept = append(kept, item)
+	}
+
+	for _, item := range kept {
+		if item.theirs {
+			c.append(item.key, item.val, item.expires)
+		} else if idx, ok := c.index(item.key); ok {
+			c.replaceValue(idx, item.val)
+			c.expires[idx] = item.expires
+			c.promote(idx)
+			c.meta[idx].version = c.nextVersion()
+			c.weigh(idx)
+		}
+	}
+}
+
+func resolveConflict[K comparable, V any](key K, ours, theirs V, onConflict func(key K, ours, theirs V) V) V {
+	if onConflict == nil {
+		return theirs
+	}
+
+	return onConflict(key, ours, theirs)
+}
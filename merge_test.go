@@ -0,0 +1,49 @@
+package lru
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func ExampleLRU_Merge() {
+	a := New[string, int](8)
+	b := New[string, int](8)
+
+	a.Set("a1", 1)
+	a.Set("a2", 2)
+	b.Set("b1", 1)
+	b.Set("b2", 2)
+
+	a.Merge(b, nil)
+	fmt.Println(a.Keys())
+
+	// Output: [a1 b1 a2 b2]
+}
+
+func TestMergeConflictAndCapacity(t *testing.T) {
+	var evicted []int
+
+	a := New(3, func(k int, _ int) {
+		evicted = append(evicted, k)
+	})
+	b := NewThreadSafe[int, int](8)
+
+	a.Set(1, 1)
+	a.Set(2, 2)
+	b.Set(2, 20)
+	b.Set(3, 3)
+	b.Set(4, 4)
+
+	a.Merge(b, func(_ int, ours, theirs int) int {
+		return ours + theirs
+	})
+
+	if !slices.Equal(a.Keys(), []int{3, 2, 4}) || !slices.Equal(evicted, []int{1}) {
+		t.Fatalf("expected the oldest item to be evicted, got %v and evicts %v", a.Keys(), evicted)
+	}
+
+	if v, _ := a.Get(2); v != 22 {
+		t.Fatalf("expected conflicting values to be resolved, got %d", v)
+	}
+}
@@ -0,0 +1,51 @@
+package lru
+
+import "expvar"
+
+// MetricsSink receives cache events, e.g. to export cache health. A sink used by a thread-safe
+// cache must be safe for concurrent use.
+type MetricsSink interface {
+	// A Get found an item.
+	Hit()
+
+	// A Get didn't find an item.
+	Miss()
+
+	// An item was evicted due to capacity.
+	Evict()
+}
+
+var _ MetricsSink = (*ExpvarMetrics)(nil)
+
+// ExpvarMetrics publishes hits, misses and evictions as an expvar map.
+type ExpvarMetrics struct {
+	m *expvar.Map
+}
+
+// Publish a new expvar map with the given name. Panics if the name is already in use, just like
+// expvar.Publish.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{
+		m: expvar.NewMap(name),
+	}
+}
+
+// Hit implements MetricsSink.
+func (e *ExpvarMetrics) Hit() {
+	e.m.Add("hits", 1)
+}
+
+// Miss implements MetricsSink.
+func (e *ExpvarMetrics) Miss() {
+	e.m.Add("misses", 1)
+}
+
+// Evict implements MetricsSink.
+func (e *ExpvarMetrics) Evict() {
+	e.m.Add("evictions", 1)
+}
+
+// The underlying expvar map.
+func (e *ExpvarMetrics) Map() *expvar.Map {
+	return e.m
+}
@@ -0,0 +1,42 @@
+package lru
+
+import "testing"
+
+type testMetrics struct {
+	hits, misses, evictions int
+}
+
+func (m *testMetrics) Hit()   { m.hits++ }
+func (m *testMetrics) Miss()  { m.misses++ }
+func (m *testMetrics) Evict() { m.evictions++ }
+
+func TestMetrics(t *testing.T) {
+	var m testMetrics
+
+	cache := NewWithOptions(2, WithMetrics[int, int](&m))
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Get(1)
+	cache.Get(3)
+	cache.Set(3, 3)
+
+	if m != (testMetrics{hits: 1, misses: 1, evictions: 1}) {
+		t.Fatalf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	m := NewExpvarMetrics("lru_test")
+
+	cache := NewWithOptions(1, WithMetrics[int, int](m))
+	cache.Set(1, 1)
+	cache.Get(1)
+	cache.Get(2)
+	cache.Set(2, 2)
+
+	for _, name := range []string{"hits", "misses", "evictions"} {
+		if v := m.Map().Get(name); v == nil || v.String() != "1" {
+			t.Fatalf("expected %s to be 1, got %v", name, v)
+		}
+	}
+}
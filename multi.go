@@ -0,0 +1,65 @@
+package lru
+
+import "iter"
+
+// MultiLRU is a cache of multiple values per key. Each key holds up to a bounded number of values
+// in insertion order, where adding a value beyond the bound drops the oldest one. Keys are evicted
+// in least recently used order, and adding a value marks its key as most recently used.
+type MultiLRU[K comparable, V any] struct {
+	c         LRU[K, []V]
+	maxValues int
+}
+
+// Create a multi-value cache of at most capacity keys, with at most maxValues values each. Not
+// thread-safe.
+func NewMulti[K comparable, V any](capacity, maxValues int, opts ...Option[K, []V]) *MultiLRU[K, V] {
+	return &MultiLRU[K, V]{c: NewWithOptions(capacity, opts...), maxValues: max(maxValues, 1)}
+}
+
+// Create a thread-safe multi-value cache of at most capacity keys, with at most maxValues values
+// each.
+func NewThreadSafeMulti[K comparable, V any](capacity, maxValues int, opts ...Option[K, []V]) *MultiLRU[K, V] {
+	return &MultiLRU[K, V]{c: NewThreadSafeWithOptions(capacity, opts...), maxValues: max(maxValues, 1)}
+}
+
+// Number of keys.
+func (m *MultiLRU[K, V]) Len() int {
+	return m.c.Len()
+}
+
+func (m *MultiLRU[K, V]) Has(key K) bool {
+	return m.c.Has(key)
+}
+
+// Add a value to a key, after any existing values, and mark the key as most recently used.
+// Returns false if there is no room for a new key.
+func (m *MultiLRU[K, V]) Add(key K, val V) (ok bool) {
+	_, ok = m.c.Compute(key, func(old []V, _ bool) ([]V, bool) {
+		// Values are never changed in place, so that returned slices stay intact
+		drop := max(len(old)-m.maxValues+1, 0)
+		vals := make([]V, 0, len(old)-drop+1)
+		vals = append(vals, old[drop:]...)
+
+		return append(vals, val), false
+	})
+
+	return
+}
+
+// Get all values of a key, oldest first, and mark the key as most recently used. The slice must
+// not be modified.
+func (m *MultiLRU[K, V]) GetAll(key K) []V {
+	vals, _ := m.c.Get(key)
+	return vals
+}
+
+// Remove a key and all its values.
+func (m *MultiLRU[K, V]) Remove(key K) (existed bool) {
+	return m.c.Remove(key)
+}
+
+// Iterate all keys and their values, from least to most recently used. The slices must not be
+// modified.
+func (m *MultiLRU[K, V]) Iterate() iter.Seq2[K, []V] {
+	return m.c.IterateAsc()
+}
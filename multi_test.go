@@ -0,0 +1,38 @@
+package lru
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMultiLRU(t *testing.T) {
+	cache := NewThreadSafeMulti[string, int](2, 3)
+
+	for i := range 4 {
+		cache.Add("a", i)
+	}
+
+	vals := cache.GetAll("a")
+
+	if !slices.Equal(vals, []int{1, 2, 3}) {
+		t.Fatalf("expected the oldest value to be dropped, got %v", vals)
+	}
+
+	cache.Add("a", 4)
+
+	if !slices.Equal(vals, []int{1, 2, 3}) {
+		t.Fatalf("expected a returned slice to stay intact, got %v", vals)
+	}
+
+	cache.Add("b", 1)
+	cache.GetAll("a")
+	cache.Add("c", 1)
+
+	if !cache.Has("a") || cache.Has("b") || cache.Len() != 2 {
+		t.Fatal("expected the least recently used key to be evicted")
+	}
+
+	if !cache.Remove("a") || cache.GetAll("a") != nil {
+		t.Fatal("expected the key to be removed")
+	}
+}
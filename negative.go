@@ -0,0 +1,99 @@
+package lru
+
+import (
+	"errors"
+	"time"
+)
+
+// Number of errors remembered by WithNegativeTTL for a cache without a positive capacity, e.g. an
+// unbounded one.
+const DefaultNegativeCapacity = 1024
+
+// Settings and remembered errors of negative caching.
+type negatives[K comparable] struct {
+	ttl     int64
+	matches []error
+	errs    map[K]negative
+}
+
+type negative struct {
+	err     error
+	expires int64
+}
+
+// Remember errors returned by the setter of GetOrSet, GetOrSetTTL and GetOrSetExpiring for ttl,
+// and return them without calling the setter again until they expire. If any errors are
+// provided, only errors matching one of them (according to errors.Is) are remembered, e.g. a
+// not found error. At most as many errors as the capacity are remembered, or
+// DefaultNegativeCapacity if the capacity is zero or less.
+func WithNegativeTTL[K comparable, V any](ttl time.Duration, errs ...error) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.negatives.ttl = int64(ttl)
+		c.negatives.matches = errs
+	}
+}
+
+// A remembered, unexpired error of a key.
+func (c *lru[K, V]) negativeErr(key K) error {
+	key = c.canonical(key)
+	n, ok := c.negatives.errs[key]
+
+	if !ok {
+		return nil
+	}
+
+	if n.expires <= c.now() {
+		delete(c.negatives.errs, key)
+		return nil
+	}
+
+	return n.err
+}
+
+// Remember a setter error, if negative caching is enabled and the error matches.
+func (c *lru[K, V]) rememberErr(key K, err error) {
+	if c.negatives.ttl <= 0 || !c.negatives.match(err) {
+		return
+	}
+
+	now := c.now()
+
+	if c.negatives.errs == nil {
+		c.negatives.errs = make(map[K]negative)
+	} else if len(c.negatives.errs) >= c.negativeCapacity() {
+		for k, n := range c.negatives.errs {
+			if n.expires <= now {
+				delete(c.negatives.errs, k)
+			}
+		}
+
+		if len(c.negatives.errs) >= c.negativeCapacity() {
+			return
+		}
+	}
+
+	c.negatives.errs[c.canonical(key)] = negative{err: err, expires: now + c.negatives.ttl}
+}
+
+// Maximum number of remembered errors.
+func (c *lru[K, V]) negativeCapacity() int {
+	if c.capacity > 0 {
+		return c.capacity
+	}
+
+	return DefaultNegativeCapacity
+}
+
+func (n *negatives[K]) match(err error) bool {
+	if len(n.matches) == 0 {
+		return true
+	}
+
+	for _, target := range n.matches {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,76 @@
+package lru
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNegativeTTL(t *testing.T) {
+	var (
+		errNotFound = errors.New("not found")
+		errTimeout  = errors.New("timeout")
+	)
+
+	clock := newFakeClock()
+	cache := NewWithOptions(8, WithClock[int, int](clock), WithNegativeTTL[int, int](time.Second, errNotFound))
+	calls := 0
+	setter := func(k int) (int, error) {
+		calls++
+
+		if k == 1 {
+			return 0, errNotFound
+		}
+
+		return 0, errTimeout
+	}
+
+	for range 3 {
+		if _, err := cache.GetOrSet(1, setter); err != errNotFound {
+			t.Fatalf("expected the not found error, got %v", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected a remembered error to skip the setter, got %d calls", calls)
+	}
+
+	cache.GetOrSet(2, setter)
+	cache.GetOrSet(2, setter)
+
+	if calls != 3 {
+		t.Fatalf("expected unmatched errors to not be remembered, got %d calls", calls)
+	}
+
+	clock.Advance(time.Second)
+	cache.GetOrSet(1, setter)
+
+	if calls != 4 {
+		t.Fatalf("expected a remembered error to expire, got %d calls", calls)
+	}
+
+	cache.GetOrSet(1, setter, ForceRefresh())
+
+	if calls != 5 {
+		t.Fatalf("expected ForceRefresh to ignore remembered errors, got %d calls", calls)
+	}
+}
+
+func TestNegativeTTLUnbounded(t *testing.T) {
+	calls := 0
+	cache := NewWithOptions(0, WithUnbounded[int, int](), WithNegativeTTL[int, int](time.Minute))
+	setter := func(int) (int, error) {
+		calls++
+		return 0, errors.New("not found")
+	}
+
+	for range 2 {
+		for i := range 10 {
+			cache.GetOrSet(i, setter)
+		}
+	}
+
+	if calls != 10 {
+		t.Fatalf("expected an unbounded cache to remember every error, got %d calls", calls)
+	}
+}
@@ -0,0 +1,358 @@
+package lru
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Option configures a cache on creation.
+type Option[K comparable, V any] func(c *lru[K, V])
+
+// Create a cache with options. Not thread-safe.
+func NewWithOptions[K comparable, V any](capacity int, opts ...Option[K, V]) LRU[K, V] {
+	c := new(lru[K, V])
+	c.init(capacity, opts)
+	return c
+}
+
+// Create a thread-safe cache with options.
+func NewThreadSafeWithOptions[K comparable, V any](capacity int, opts ...Option[K, V]) LRU[K, V] {
+	t := new(threadsafe[K, V])
+	t.lru.init(capacity, opts)
+	t.start()
+
+	return t
+}
+
+// Notify each evicted item. This equals the evicted argument of New and NewThreadSafe.
+func WithEvicted[K comparable, V any](evicted func(key K, val V)) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.evicted = evicted
+	}
+}
+
+// Notify an internal hook of each evict, before any callback of WithEvicted.
+func withEvictHook[K comparable, V any](hook func(key K, val V)) Option[K, V] {
+	return func(c *lru[K, V]) {
+		evicted := c.evicted
+
+		if evicted == nil {
+			c.evicted = hook
+			return
+		}
+
+		c.evicted = func(key K, val V) {
+			hook(key, val)
+			evicted(key, val)
+		}
+	}
+}
+
+// Notify each new item, as opposed to an overwritten one.
+func WithInserted[K comparable, V any](inserted func(key K, val V)) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.inserted = inserted
+	}
+}
+
+// Notify each hit. The function must be safe for concurrent use when used by a thread-safe cache
+// with sampled or buffered promotion, as hits are then served under the shared read lock.
+func WithHit[K comparable, V any](hit func(key K, val V)) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.onHit = hit
+	}
+}
+
+// Copy values on their way in and out of the cache, i.e. when set and when returned by gets,
+// iterators, Oldest, Newest, Entry and Snapshot. Makes the cache safe for mutable values, such
+// as slices and maps.
+func WithCopier[K comparable, V any](copier func(V) V) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.copier = copier
+	}
+}
+
+// Release each value evicted, removed, overwritten or reset, after any evict notice, e.g. to
+// recycle its memory. Values returned by GetAndRemove, Swap, RemoveOldest, RemoveNewest, SetEvict
+// and ReplaceEvict aren't released, nor are values of a clone or snapshot, as they share values
+// with the cache, nor a value overwritten by itself. Only use when values aren't retained after
+// leaving the cache, or with WithCopier.
+func WithReleaser[K comparable, V any](release func(val V)) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.releaser = release
+	}
+}
+
+// Release values into a pool, as with WithReleaser, so that setters of GetOrSet can allocate new
+// values from it. V should be a pointer, so that putting values doesn't allocate.
+func WithPool[K comparable, V any](pool *sync.Pool) Option[K, V] {
+	return WithReleaser[K](func(val V) {
+		pool.Put(val)
+	})
+}
+
+// Close each value that implements io.Closer, either by value or by pointer, when it's released as
+// with WithReleaser. Values are closed outside the lock of a thread-safe cache, and leased values
+// once released. Close errors are ignored.
+func WithAutoClose[K comparable, V any]() Option[K, V] {
+	return WithReleaser[K](func(val V) {
+		if c, ok := any(val).(io.Closer); ok {
+			c.Close()
+		} else if c, ok := any(&val).(io.Closer); ok {
+			c.Close()
+		}
+	})
+}
+
+// Panic on Reset if the cache has an evict callback, whose notices Reset would skip, or releases
+// values with WithReleaser, WithPool or WithAutoClose, so that RemoveAll is used instead. Close is
+// unaffected.
+func WithStrictReset[K comparable, V any]() Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.strict = true
+	}
+}
+
+// Report the memory referenced by each item in SizeBytes, beyond the fixed size of its key and
+// value types, e.g. the bytes of a string or slice.
+func WithSizer[K comparable, V any](sizer func(key K, val V) int) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.sizer = sizer
+	}
+}
+
+// Use a custom clock for expiry and event times, instead of the system clock.
+func WithClock[K comparable, V any](clock Clock) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.clock = clock
+	}
+}
+
+// Record when each item was created and last accessed, as reported by Entry. Costs a clock read
+// on each insert and hit.
+func WithEntryTimes[K comparable, V any]() Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.entryTimes = true
+	}
+}
+
+// Treat a capacity of zero or less as unbounded, instead of a disabled cache that rejects all
+// items.
+func WithUnbounded[K comparable, V any]() Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.unbounded = true
+	}
+}
+
+// Notify each item that is rejected due to lack of room, either because the cache is disabled
+// or because all items are pinned.
+func WithRejected[K comparable, V any](rejected func(key K, val V)) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.rejected = rejected
+	}
+}
+
+// Report hits, misses and evictions to a metrics sink.
+func WithMetrics[K comparable, V any](sink MetricsSink) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.metrics = sink
+	}
+}
+
+// Emit an event for each hit, miss, insert and eviction. The function is called synchronously, and
+// must be safe for concurrent use when used by a thread-safe cache.
+func WithEvents[K comparable, V any](fn func(Event[K])) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.events = fn
+	}
+}
+
+// Only promote 1 in n hits of a thread-safe cache to most recently used. Gets then only take the
+// shared read lock, except when promoting, which suits read-mostly workloads at the cost of an
+// approximate recency order. Ignored by caches that aren't thread-safe.
+func WithSampledPromotion[K comparable, V any](n int) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.promoteEvery = uint64(max(n, 1))
+	}
+}
+
+// Buffer up to size hits of a thread-safe cache, and promote them to most recently used in a
+// batch once the write lock is next held. Gets then only take the shared read lock, while keeping
+// a near-exact recency order. Hits beyond a full buffer aren't promoted. Takes precedence over
+// WithSampledPromotion, and is ignored by caches that aren't thread-safe.
+func WithBufferedPromotion[K comparable, V any](size int) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.promoteBuffer = size
+	}
+}
+
+// Refresh items of a thread-safe cache in the background, when hit by GetOrSet, GetOrSetTTL or
+// GetOrSetExpiring within window of their expiry. The stale value is returned meanwhile, and the
+// setter is called without holding the lock. Ignored by caches that aren't thread-safe.
+func WithRefreshAhead[K comparable, V any](window time.Duration) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.refreshAhead = int64(window)
+	}
+}
+
+// Remove expired items of a thread-safe cache every interval in a background goroutine, which is
+// stopped by Close. Expired items are otherwise only removed when overwritten or evicted. Ignored
+// by caches that aren't thread-safe.
+func WithJanitor[K comparable, V any](interval time.Duration) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.janitor = interval
+	}
+}
+
+// Notify each remaining item as evicted on Close.
+func WithEvictOnClose[K comparable, V any]() Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.closeEvict = true
+	}
+}
+
+// Remember the n most recently evicted keys, so that Recommendation can estimate the effect of a
+// larger capacity. Each miss scans the remembered keys.
+func WithGhosts[K comparable, V any](n int) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.ghosts = newGhosts[K](n)
+	}
+}
+
+func evictedOption[K comparable, V any](evicted []func(key K, val V)) []Option[K, V] {
+	if len(evicted) == 0 || evicted[0] == nil {
+		return nil
+	}
+
+	return []Option[K, V]{WithEvicted(evicted[0])}
+}
+
+// CallOption alters a single Get or GetOrSet call.
+type CallOption uint8
+
+const (
+	bypass CallOption = 1 << iota
+	forceRefresh
+)
+
+// Skip the cache. Get always misses, and GetOrSet calls the setter without storing its value.
+func Bypass() CallOption {
+	return bypass
+}
+
+// Ignore any cached value. Get always misses, and GetOrSet calls the setter and overwrites any
+// cached value.
+func ForceRefresh() CallOption {
+	return forceRefresh
+}
+
+// Policy selects the item that is evicted due to capacity.
+type Policy uint8
+
+const (
+	// Evict the least recently used item.
+	PolicyLRU Policy = iota
+
+	// Evict the first inserted item, regardless of use. Only Touch promotes items.
+	PolicyFIFO
+
+	// Evict the most recently used item, which suits cyclic scans larger than the cache.
+	PolicyMRU
+
+	// Evict by S3-FIFO, which admits new items to a small FIFO queue, and moves those hit again
+	// to a main FIFO queue. Keys recently evicted from the small queue are remembered, and enter
+	// the main queue directly if added again. Reads only count hits instead of reordering items,
+	// which resists scans and suits skewed workloads, e.g. web traffic. Iteration order, Oldest
+	// and Newest then reflect the main queue followed by the small queue.
+	PolicyS3FIFO
+)
+
+// Select the eviction policy. Defaults to PolicyLRU. Ignored with WithVictimSelector,
+// WithSampledEviction and WithCostFn.
+func WithPolicy[K comparable, V any](policy Policy) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.mru = policy == PolicyMRU
+
+		if policy == PolicyFIFO || policy == PolicyS3FIFO {
+			c.noPromote = PromoteOnBoth
+		}
+
+		if policy == PolicyS3FIFO {
+			c.s3 = newS3FIFO[K]()
+		} else {
+			c.s3 = nil
+		}
+	}
+}
+
+// PromoteOn selects the operations that mark an existing item as most recently used.
+type PromoteOn uint8
+
+const (
+	// Gets and other reads, except Touch, which always promotes.
+	PromoteOnGet PromoteOn = 1 << iota
+
+	// Overwrites by Replace, Upsert and other writes.
+	PromoteOnSet
+
+	PromoteOnBoth = PromoteOnGet | PromoteOnSet
+)
+
+// Only promote existing items on the selected operations, e.g. PromoteOnSet to keep scans from
+// reordering the cache, which then evicts in the order of writes regardless of reads. Defaults to
+// PromoteOnBoth.
+func WithPromoteOn[K comparable, V any](on PromoteOn) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.noPromote = PromoteOnBoth &^ on
+	}
+}
+
+// ResizeOption alters a single Resize call.
+type ResizeOption uint8
+
+const (
+	resizeLazy ResizeOption = 1 << iota
+	resizeNewestFirst
+	resizeKeepStorage
+	resizeNow
+)
+
+// Don't evict anything right away when shrinking. Items exceeding the capacity are instead
+// evicted in steps on subsequent inserts.
+func ResizeLazy() ResizeOption {
+	return resizeLazy
+}
+
+// Evict the most recently used items first when shrinking, e.g. to keep a long-lived working set
+// after a burst of one-off items.
+func ResizeEvictNewestFirst() ResizeOption {
+	return resizeNewestFirst
+}
+
+// Keep the allocated storage when shrinking, so that growing again doesn't reallocate.
+func ResizeKeepStorage() ResizeOption {
+	return resizeKeepStorage
+}
+
+// Evict all items exceeding the capacity right away when shrinking, rather than at most a step at
+// a time, e.g. to release memory under pressure.
+func ResizeNow() ResizeOption {
+	return resizeNow
+}
+
+func resizeFlags(opts []ResizeOption) (flags ResizeOption) {
+	for _, opt := range opts {
+		flags |= opt
+	}
+
+	return
+}
+
+func callFlags(opts []CallOption) (flags CallOption) {
+	for _, opt := range opts {
+		flags |= opt
+	}
+
+	return
+}
@@ -0,0 +1,62 @@
+package lru
+
+// Exempt an item from capacity eviction. Pinned items still count toward Len, and can still be
+// removed explicitly. If all items are pinned, new items are rejected.
+func (c *lru[K, V]) Pin(key K) (ok bool) {
+	idx, ok := c.index(key)
+
+	if !ok || !c.alive(idx) {
+		return false
+	}
+
+	if !c.pinned[idx] {
+		c.pinned[idx] = true
+		c.pins++
+	}
+
+	if l := c.leases[c.keys[idx]]; l != nil {
+		l.pinned = true
+	}
+
+	return true
+}
+
+// Make a pinned item evictable again.
+func (c *lru[K, V]) Unpin(key K) (ok bool) {
+	idx, ok := c.index(key)
+
+	if !ok {
+		return
+	}
+
+	// A leased item stays pinned until released
+	if l := c.leases[c.keys[idx]]; l != nil {
+		l.pinned = false
+		return true
+	}
+
+	if c.pinned[idx] {
+		c.pinned[idx] = false
+		c.pins--
+	}
+
+	return true
+}
+
+// Index of the most recently used item that isn't pinned, or -1 if none.
+func (c *lru[K, V]) newestUnpinned() (idx int) {
+	for idx = c.newest; idx >= 0 && c.pinned[idx]; {
+		idx = c.older[idx]
+	}
+
+	return
+}
+
+// Index of the least recently used item that isn't pinned, or -1 if none.
+func (c *lru[K, V]) oldestUnpinned() (idx int) {
+	for idx = c.oldest; idx >= 0 && c.pinned[idx]; {
+		idx = c.newer[idx]
+	}
+
+	return
+}
@@ -0,0 +1,53 @@
+package lru
+
+import "testing"
+
+func TestPin(t *testing.T) {
+	cache := New[int, int](2)
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+
+	if !cache.Pin(1) || cache.Pin(3) {
+		t.Fatal("expected only existing items to be pinnable")
+	}
+
+	cache.Set(3, 3)
+
+	if !cache.Has(1) || cache.Has(2) {
+		t.Fatal("expected the oldest unpinned item to be evicted")
+	}
+
+	cache.Pin(3)
+
+	if cache.Set(4, 4) || cache.Has(4) {
+		t.Fatal("expected new items to be rejected when all items are pinned")
+	}
+
+	if cache.Len() != 2 {
+		t.Fatalf("expected 2 items, got %d", cache.Len())
+	}
+
+	cache.Unpin(1)
+
+	if !cache.Set(4, 4) || cache.Has(1) {
+		t.Fatal("expected unpinned item to be evicted")
+	}
+}
+
+func TestPinRemove(t *testing.T) {
+	cache := New[int, int](2)
+	cache.Set(1, 1)
+	cache.Pin(1)
+
+	if !cache.Remove(1) {
+		t.Fatal("expected pinned item to be removable")
+	}
+
+	cache.Set(2, 2)
+	cache.Set(3, 3)
+	cache.Set(4, 4)
+
+	if cache.Has(2) {
+		t.Fatal("expected removed pin to not affect eviction")
+	}
+}
@@ -0,0 +1,94 @@
+package lru
+
+import "slices"
+
+// Quota is a cache shared by tenants, where each tenant holds at most its own maximum number of
+// items. A tenant at its maximum evicts its own least recently used item to make room, rather than
+// those of other tenants. Otherwise, items are evicted in least recently used order regardless of
+// tenant. Not thread-safe.
+type Quota[K comparable, V any] struct {
+	c      LRU[K, V]
+	quota  func(K) (tenant string, max int)
+	counts map[string]int
+}
+
+// Create a cache of the provided total capacity and options, where quota returns the tenant of a
+// key and the tenant's maximum number of items. A callback of WithEvicted is notified of each evict.
+func NewQuota[K comparable, V any](capacity int, quota func(key K) (tenant string, max int), opts ...Option[K, V]) *Quota[K, V] {
+	q := &Quota[K, V]{
+		quota:  quota,
+		counts: make(map[string]int),
+	}
+
+	q.c = NewWithOptions(capacity, append(slices.Clip(opts), withEvictHook(q.evicted))...)
+	return q
+}
+
+// Number of items.
+func (q *Quota[K, V]) Len() int {
+	return q.c.Len()
+}
+
+// Number of items of a tenant.
+func (q *Quota[K, V]) Usage(tenant string) int {
+	return q.counts[tenant]
+}
+
+func (q *Quota[K, V]) Has(key K) bool {
+	return q.c.Has(key)
+}
+
+func (q *Quota[K, V]) Get(key K) (val V, ok bool) {
+	return q.c.Get(key)
+}
+
+// Add or overwrite an item and mark it as most recently used. If its tenant is at its maximum, the
+// tenant's least recently used item is evicted first. Returns false if the item was rejected,
+// e.g. due to a maximum of zero.
+func (q *Quota[K, V]) Set(key K, val V) (ok bool) {
+	if q.c.Has(key) {
+		q.c.Upsert(key, val)
+		return true
+	}
+
+	tenant, limit := q.quota(key)
+
+	for q.counts[tenant] >= limit {
+		// Expired items still count until removed
+		if !q.evictTenant(tenant) && q.c.PurgeExpired() == 0 {
+			return false
+		}
+	}
+
+	if q.c.Upsert(key, val); !q.c.Has(key) {
+		return false
+	}
+
+	q.counts[tenant]++
+	return true
+}
+
+// Remove an item, and notify its evict.
+func (q *Quota[K, V]) Remove(key K) (existed bool) {
+	return q.c.Remove(key)
+}
+
+// Evict the least recently used item of a tenant, and return whether there was one.
+func (q *Quota[K, V]) evictTenant(tenant string) bool {
+	for key := range q.c.IterateAsc() {
+		if t, _ := q.quota(key); t == tenant {
+			q.c.Remove(key)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (q *Quota[K, V]) evicted(key K, _ V) {
+	tenant, _ := q.quota(key)
+
+	if q.counts[tenant]--; q.counts[tenant] <= 0 {
+		delete(q.counts, tenant)
+	}
+}
@@ -0,0 +1,61 @@
+package lru
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuota(t *testing.T) {
+	cache := NewQuota[string, int](4, func(key string) (string, int) {
+		tenant, _, _ := strings.Cut(key, ":")
+
+		if tenant == "noisy" {
+			return tenant, 2
+		}
+
+		return tenant, 4
+	})
+
+	cache.Set("quiet:1", 1)
+	cache.Set("quiet:2", 2)
+
+	for i := range 5 {
+		cache.Set("noisy:"+string(rune('a'+i)), i)
+	}
+
+	if !cache.Has("quiet:1") || !cache.Has("quiet:2") || cache.Usage("noisy") != 2 {
+		t.Fatalf("expected the noisy tenant to evict its own items, got %d items", cache.Usage("noisy"))
+	}
+
+	if !cache.Has("noisy:d") || !cache.Has("noisy:e") {
+		t.Fatal("expected the newest items of the tenant to be kept")
+	}
+
+	// Below its maximum, a tenant evicts the least recently used item of any tenant
+	cache.Set("other:1", 1)
+
+	if cache.Has("quiet:1") || cache.Usage("quiet") != 1 || cache.Len() != 4 {
+		t.Fatal("expected the least recently used item to be evicted")
+	}
+
+	if !cache.Remove("noisy:d") || cache.Usage("noisy") != 1 {
+		t.Fatal("expected the removal to free tenant usage")
+	}
+}
+
+func TestQuotaEvicted(t *testing.T) {
+	var evicted []string
+
+	cache := NewQuota(4, func(string) (string, int) {
+		return "tenant", 1
+	}, WithEvicted(func(key string, _ int) {
+		evicted = append(evicted, key)
+	}))
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	if len(evicted) != 1 || cache.Usage("tenant") != 1 {
+		t.Fatalf("expected the evict callback to be notified, got %v", evicted)
+	}
+}
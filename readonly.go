@@ -0,0 +1,49 @@
+package lru
+
+import "iter"
+
+// ReadOnlyLRU is a view of a cache that can't mutate it, nor affect its recency.
+type ReadOnlyLRU[K comparable, V any] interface {
+	Len() int
+	Cap() int
+	Has(key K) (ok bool)
+
+	// Get an item without affecting recency.
+	Peek(key K) (val V, ok bool)
+
+	// Iterate all items in no particular order.
+	Iterate() iter.Seq2[K, V]
+}
+
+var _ ReadOnlyLRU[struct{}, struct{}] = readOnly[struct{}, struct{}]{}
+
+// Wrap a cache in a read-only view, e.g. to hand it to code that mustn't mutate or resize it. The
+// view can't be converted back into the cache.
+func ReadOnly[K comparable, V any](cache LRU[K, V]) ReadOnlyLRU[K, V] {
+	return readOnly[K, V]{c: cache}
+}
+
+type readOnly[K comparable, V any] struct {
+	c LRU[K, V]
+}
+
+func (r readOnly[K, V]) Len() int {
+	return r.c.Len()
+}
+
+func (r readOnly[K, V]) Cap() int {
+	return r.c.Cap()
+}
+
+func (r readOnly[K, V]) Has(key K) bool {
+	return r.c.Has(key)
+}
+
+func (r readOnly[K, V]) Peek(key K) (val V, ok bool) {
+	info, ok := r.c.Entry(key)
+	return info.Value, ok
+}
+
+func (r readOnly[K, V]) Iterate() iter.Seq2[K, V] {
+	return r.c.Iterate()
+}
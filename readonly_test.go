@@ -0,0 +1,34 @@
+package lru
+
+import "testing"
+
+func TestReadOnly(t *testing.T) {
+	cache := New[int, string](2)
+	cache.Set(1, "a")
+	cache.Set(2, "b")
+
+	view := ReadOnly(cache)
+
+	if val, ok := view.Peek(1); !ok || val != "a" {
+		t.Fatalf("expected a, got %q", val)
+	}
+
+	if _, ok := view.(LRU[int, string]); ok {
+		t.Fatal("expected the view not to expose the cache")
+	}
+
+	if view.Len() != 2 || view.Cap() != 2 || !view.Has(2) {
+		t.Fatal("unexpected view state")
+	}
+
+	// Peeking must not have promoted key 1.
+	cache.Set(3, "c")
+
+	if view.Has(1) {
+		t.Fatal("expected key 1 to be evicted")
+	}
+
+	if stats := cache.Stats(); stats.Hits != 0 {
+		t.Fatalf("expected no hits, got %d", stats.Hits)
+	}
+}
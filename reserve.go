@@ -0,0 +1,78 @@
+package lru
+
+import "iter"
+
+// Evict items until at least n items can be added without evicting, and notify each evict.
+// Returns false, without evicting anything, if pinned items or the capacity leave no room for n
+// items.
+func (c *lru[K, V]) Reserve(n int) (ok bool) {
+	if n <= 0 {
+		return true
+	}
+
+	limit := c.limit()
+
+	if limit <= 0 || n > limit-c.pins {
+		return false
+	}
+
+	for len(c.keys) > limit-n {
+		idx := c.oldestEvictable()
+
+		if idx < 0 {
+			return false
+		}
+
+		c.evictIndex(idx)
+	}
+
+	return true
+}
+
+// Reserve room for all items of a sequence, and add them as with Set. Returns false, without
+// adding anything, if the room can't be reserved. The sequence must not call the cache.
+func (c *lru[K, V]) ReserveAndSet(seq iter.Seq2[K, V]) (ok bool) {
+	return c.reserveAndSet(collectEntries(seq))
+}
+
+func (c *lru[K, V]) reserveAndSet(entries []EntryInfo[K, V]) (ok bool) {
+	if !c.Reserve(len(entries)) {
+		return false
+	}
+
+	for _, e := range entries {
+		c.Set(e.Key, e.Value)
+	}
+
+	return true
+}
+
+// Reserve implements LRU. Other goroutines may fill the reserved room, unless reserved within
+// Batch.
+func (t *threadsafe[K, V]) Reserve(n int) (ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Reserve(n)
+}
+
+// ReserveAndSet implements LRU.
+func (t *threadsafe[K, V]) ReserveAndSet(seq iter.Seq2[K, V]) (ok bool) {
+	// Collect the items first, so that the sequence runs without the lock
+	entries := collectEntries(seq)
+
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.reserveAndSet(entries)
+}
+
+func collectEntries[K comparable, V any](seq iter.Seq2[K, V]) []EntryInfo[K, V] {
+	var entries []EntryInfo[K, V]
+
+	for k, v := range seq {
+		entries = append(entries, EntryInfo[K, V]{Key: k, Value: v})
+	}
+
+	return entries
+}
@@ -0,0 +1,50 @@
+package lru
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestReserve(t *testing.T) {
+	var evicted []int
+
+	cache := NewThreadSafe(4, func(key, _ int) {
+		evicted = append(evicted, key)
+	})
+
+	for i := range 4 {
+		cache.Set(i, i)
+	}
+
+	cache.Pin(0)
+
+	if cache.Reserve(4) || len(evicted) != 0 {
+		t.Fatalf("expected pinned items to leave no room, evicted %v", evicted)
+	}
+
+	if !cache.Reserve(2) || cache.Available() != 2 || len(evicted) != 2 {
+		t.Fatalf("expected room for 2 items, evicted %v", evicted)
+	}
+
+	cache.Batch(func(tx Tx[int, int]) {
+		if !tx.Reserve(3) {
+			t.Fatal("expected room for 3 items")
+		}
+
+		for i := 10; i < 13; i++ {
+			tx.Set(i, i)
+		}
+	})
+
+	if len(evicted) != 3 || cache.Len() != 4 {
+		t.Fatalf("expected no eviction after reserving, evicted %v", evicted)
+	}
+
+	if cache.ReserveAndSet(maps.All(map[int]int{20: 20, 21: 21, 22: 22, 23: 23})) {
+		t.Fatal("expected no room for 4 items beside a pinned one")
+	}
+
+	if !cache.ReserveAndSet(maps.All(map[int]int{20: 20, 21: 21})) || !cache.Has(20) || !cache.Has(21) {
+		t.Fatal("expected the items to be added")
+	}
+}
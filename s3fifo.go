@@ -0,0 +1,207 @@
+package lru
+
+import (
+	"maps"
+	"slices"
+	"sync/atomic"
+)
+
+// Create a cache that evicts by S3-FIFO, as with WithPolicy(PolicyS3FIFO). Not thread-safe.
+func NewS3FIFO[K comparable, V any](capacity int, opts ...Option[K, V]) LRU[K, V] {
+	return NewWithOptions(capacity, append([]Option[K, V]{WithPolicy[K, V](PolicyS3FIFO)}, opts...)...)
+}
+
+// Create a thread-safe cache that evicts by S3-FIFO, as with WithPolicy(PolicyS3FIFO). Reads only
+// take a read lock, as they never reorder the cache.
+func NewThreadSafeS3FIFO[K comparable, V any](capacity int, opts ...Option[K, V]) LRU[K, V] {
+	return NewThreadSafeWithOptions(capacity, append([]Option[K, V]{WithPolicy[K, V](PolicyS3FIFO)}, opts...)...)
+}
+
+// Maximum number of hits counted per item by S3-FIFO.
+const s3MaxFreq = 3
+
+// Queues of S3-FIFO. Both share the recency order of the cache, with the main queue first:
+// items of the small queue are always newer than those of the main queue.
+type s3fifo[K comparable] struct {
+	split  int // Index of the oldest item of the small queue, or -1 if empty
+	small  int // Number of items in the small queue
+	ghosts map[K]uint64
+	order  []ghostKey[K] // Keys of ghosts, from oldest to newest
+	seq    uint64
+}
+
+// Key recently evicted from the small queue, which is admitted to the main queue if added again.
+type ghostKey[K comparable] struct {
+	key K
+	seq uint64
+}
+
+func newS3FIFO[K comparable]() *s3fifo[K] {
+	return &s3fifo[K]{split: -1}
+}
+
+func (s *s3fifo[K]) clone() *s3fifo[K] {
+	dst := *s
+	dst.ghosts = maps.Clone(s.ghosts)
+	dst.order = slices.Clone(s.order)
+	return &dst
+}
+
+func (s *s3fifo[K]) reset() {
+	s.split = -1
+	s.small = 0
+	s.ghosts = nil
+	s.order = nil
+}
+
+// Remember a key evicted from the small queue, and forget the oldest ghosts beyond limit.
+func (s *s3fifo[K]) haunt(key K, limit int) {
+	if s.ghosts == nil {
+		s.ghosts = make(map[K]uint64)
+	}
+
+	s.seq++
+	s.ghosts[key] = s.seq
+	s.order = append(s.order, ghostKey[K]{key, s.seq})
+
+	for len(s.order) > limit {
+		if g := s.order[0]; s.ghosts[g.key] == g.seq {
+			delete(s.ghosts, g.key)
+		}
+
+		s.order[0] = ghostKey[K]{}
+		s.order = s.order[1:]
+	}
+}
+
+// Forget a ghost, and report whether it existed.
+func (s *s3fifo[K]) exorcise(key K) (ok bool) {
+	if _, ok = s.ghosts[key]; ok {
+		delete(s.ghosts, key)
+	}
+
+	return
+}
+
+// Number of items of the small queue at capacity.
+func (c *lru[K, V]) smallCap() int {
+	return max(c.limit()/10, 1)
+}
+
+// Queue a new item, which is linked as the newest. A ghost enters the main queue, and any other
+// key the small queue.
+func (c *lru[K, V]) admit(idx int) {
+	s := c.s3
+
+	if s.exorcise(c.keys[idx]) {
+		c.unlink(idx)
+		c.linkMain(idx)
+		return
+	}
+
+	c.meta[idx].small = true
+	s.small++
+
+	if s.split < 0 {
+		s.split = idx
+	}
+}
+
+// Take an item out of its queue, before it's unlinked.
+func (c *lru[K, V]) dequeue(idx int) {
+	s := c.s3
+
+	if !c.meta[idx].small {
+		return
+	}
+
+	if s.small--; s.split == idx {
+		s.split = c.newer[idx]
+	}
+}
+
+// Link an unlinked item as the newest of the main queue.
+func (c *lru[K, V]) linkMain(idx int) {
+	split := c.s3.split
+
+	if split < 0 {
+		c.link(idx)
+		return
+	}
+
+	c.gen++
+	older := c.older[split]
+	c.older[idx], c.newer[idx] = older, split
+	c.older[split] = idx
+
+	if older >= 0 {
+		c.newer[older] = idx
+	} else {
+		c.oldest = idx
+	}
+}
+
+// Hits of an item since it entered its queue, up to s3MaxFreq.
+func (c *lru[K, V]) freq(idx int) uint64 {
+	m := &c.meta[idx]
+
+	if hits := atomic.LoadUint64(&m.hits); hits > m.mark {
+		return min(hits-m.mark, s3MaxFreq)
+	}
+
+	return 0
+}
+
+// Index of the next item to evict by S3-FIFO, or -1 if all items are pinned. Items of the small
+// queue that have been hit move to the main queue, and items of the main queue that have been hit
+// are reinserted with one hit less, until an item that hasn't been hit is found. Pinned items are
+// treated as hit.
+func (c *lru[K, V]) s3Victim() int {
+	if c.pins >= len(c.keys) {
+		return -1
+	}
+
+	s := c.s3
+	smallCap := c.smallCap()
+
+	// Every item moves at most once from the small queue, and is reinserted at most s3MaxFreq
+	// times, unless pinned
+	for range (s3MaxFreq + 2) * len(c.keys) {
+		if s.small > 0 && (s.small >= smallCap || s.split == c.oldest) {
+			idx := s.split
+
+			if !c.pinned[idx] && c.freq(idx) == 0 {
+				return idx
+			}
+
+			s.split = c.newer[idx]
+			s.small--
+			c.meta[idx].small = false
+			c.meta[idx].mark = atomic.LoadUint64(&c.meta[idx].hits)
+			continue
+		}
+
+		idx := c.oldest
+
+		if !c.pinned[idx] {
+			f := c.freq(idx)
+
+			if f == 0 {
+				return idx
+			}
+
+			c.meta[idx].mark = atomic.LoadUint64(&c.meta[idx].hits) - (f - 1)
+		}
+
+		c.unlink(idx)
+		c.linkMain(idx)
+	}
+
+	return c.oldestUnpinned()
+}
+
+// Metadata of an overwritten item, which stays in its queue.
+func (c *lru[K, V]) requeue(old, m entryMeta) entryMeta {
+	m.small = old.small
+	return m
+}
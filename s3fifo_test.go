@@ -0,0 +1,90 @@
+package lru
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestS3FIFO(t *testing.T) {
+	for _, cache := range []LRU[int, int]{NewS3FIFO[int, int](10), NewThreadSafeS3FIFO[int, int](10)} {
+		for i := range 5 {
+			cache.Set(i, i)
+			cache.Get(i)
+		}
+
+		// A scan of keys that are never hit again only churns the small queue
+		for i := 100; i < 200; i++ {
+			cache.Set(i, i)
+		}
+
+		for i := range 5 {
+			if !cache.Has(i) {
+				t.Fatalf("expected hit item %d to survive a scan", i)
+			}
+		}
+
+		if cache.Len() != 10 {
+			t.Fatalf("expected a full cache, got %d items", cache.Len())
+		}
+
+		// A recently evicted key is remembered, and enters the main queue
+		ghost := 190
+
+		if cache.Has(ghost) {
+			t.Fatal("expected an unhit item to be evicted")
+		}
+
+		cache.Set(ghost, ghost)
+
+		for i := 200; i < 300; i++ {
+			cache.Set(i, i)
+		}
+
+		if !cache.Has(ghost) {
+			t.Fatal("expected a ghost to be admitted to the main queue")
+		}
+	}
+}
+
+func TestS3FIFORandom(t *testing.T) {
+	cache := NewS3FIFO[int, int](50).(*lru[int, int])
+
+	for i := range 20000 {
+		key := rand.IntN(200)
+
+		switch rand.IntN(10) {
+		case 0:
+			cache.Remove(key)
+		case 1:
+			cache.Pin(key)
+		case 2:
+			cache.Unpin(key)
+		case 3, 4:
+			cache.Replace(key, i)
+		default:
+			if _, ok := cache.Get(key); !ok {
+				cache.Set(key, i)
+			}
+		}
+
+		small, split := 0, -1
+
+		for idx := range cache.ascending() {
+			if cache.meta[idx].small {
+				if small++; split < 0 {
+					split = idx
+				}
+			} else if split >= 0 {
+				t.Fatal("expected the small queue to follow the main queue")
+			}
+		}
+
+		if small != cache.s3.small || split != cache.s3.split {
+			t.Fatalf("expected %d small items from %d, got %d from %d", small, split, cache.s3.small, cache.s3.split)
+		}
+
+		if cache.Len() > 50 {
+			t.Fatalf("expected at most 50 items, got %d", cache.Len())
+		}
+	}
+}
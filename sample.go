@@ -0,0 +1,39 @@
+package lru
+
+import "math/rand/v2"
+
+// Evict the least recently used of n randomly sampled items, rather than the exact least recently
+// used, as with Redis. Uses only record the time of use, instead of reordering the items, which
+// suits huge caches with a lot of churn. Iteration order, Oldest and Newest then reflect insertion
+// rather than use. Ignored with WithVictimSelector, and takes precedence over WithCostFn.
+func WithSampledEviction[K comparable, V any](n int) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.samples = max(n, 1)
+	}
+}
+
+// Index of the least recently used unpinned item of a random sample, or -1 if all items are
+// pinned.
+func (c *lru[K, V]) sampleVictim() (idx int) {
+	idx = -1
+
+	for range c.samples {
+		i := rand.IntN(len(c.keys))
+
+		if !c.pinned[i] && (idx < 0 || c.meta[i].stamp < c.meta[idx].stamp) {
+			idx = i
+		}
+	}
+
+	// Only pinned items were sampled
+	if idx < 0 && c.pins > 0 {
+		idx = c.oldestUnpinned()
+	}
+
+	return
+}
+
+func (c *lru[K, V]) nextStamp() uint64 {
+	c.stamp++
+	return c.stamp
+}
@@ -0,0 +1,31 @@
+package lru
+
+import "testing"
+
+func TestSampledEviction(t *testing.T) {
+	cache := NewWithOptions(1000, WithSampledEviction[int, int](8))
+
+	for i := range 1000 {
+		cache.Set(i, i)
+	}
+
+	for i := range 500 {
+		cache.Get(i)
+	}
+
+	for i := range 500 {
+		cache.Set(1000+i, i)
+	}
+
+	var kept int
+
+	for i := range 500 {
+		if cache.Has(i) {
+			kept++
+		}
+	}
+
+	if cache.Len() != 1000 || kept < 400 {
+		t.Fatalf("expected most recently used items to be kept, got %d of 500", kept)
+	}
+}
@@ -0,0 +1,34 @@
+package lru
+
+import "unsafe"
+
+// Estimate the memory held by the cache in bytes, i.e. its allocated storage plus the size of each
+// item reported by WithSizer.
+func (c *lru[K, V]) SizeBytes() (size int64) {
+	var (
+		key K
+		val V
+	)
+
+	perItem := unsafe.Sizeof(key) + unsafe.Sizeof(val) + 2*unsafe.Sizeof(int(0)) +
+		unsafe.Sizeof(int64(0)) + unsafe.Sizeof(false) + unsafe.Sizeof(entryMeta{})
+
+	size = int64(cap(c.keys)) * int64(perItem)
+	size += int64(len(c.aliases)) * int64(2*unsafe.Sizeof(key))
+
+	if c.sizer != nil {
+		for i := range c.keys {
+			size += int64(c.sizer(c.keys[i], c.vals[i]))
+		}
+	}
+
+	return
+}
+
+// SizeBytes implements LRU.
+func (t *threadsafe[K, V]) SizeBytes() int64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.SizeBytes()
+}
@@ -0,0 +1,21 @@
+package lru
+
+import "testing"
+
+func TestSizeBytes(t *testing.T) {
+	cache := NewWithOptions(8, WithSizer(func(_ int, v []byte) int {
+		return cap(v)
+	}))
+
+	empty := cache.SizeBytes()
+
+	if empty <= 0 {
+		t.Fatal("expected preallocated storage to be counted")
+	}
+
+	cache.Set(1, make([]byte, 1000))
+
+	if size := cache.SizeBytes(); size != empty+1000 {
+		t.Fatalf("expected sized items to be counted, got %d", size-empty)
+	}
+}
@@ -0,0 +1,168 @@
+package lru
+
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Copy all items and settings into an empty cache, but not stats.
+func (c *lru[K, V]) cloneInto(dst *lru[K, V]) {
+	dst.keys = slices.Clone(c.keys)
+	dst.vals = slices.Clone(c.vals)
+	dst.older = slices.Clone(c.older)
+	dst.newer = slices.Clone(c.newer)
+	dst.expires = slices.Clone(c.expires)
+	dst.pinned = slices.Clone(c.pinned)
+	dst.meta = slices.Clone(c.meta)
+	dst.pins = c.pins
+	dst.aliases = maps.Clone(c.aliases)
+	dst.tags, dst.tagsOf = c.cloneTags()
+	dst.oldest = c.oldest
+	dst.newest = c.newest
+	dst.version = c.version
+	dst.capacity = c.capacity
+	dst.resizing = c.resizing
+	dst.unbounded = c.unbounded
+	dst.entryTimes = c.entryTimes
+	dst.closed = c.closed
+	dst.closeEvict = c.closeEvict
+	dst.clock = c.clock
+	dst.evicted = c.evicted
+	dst.rejected = c.rejected
+	dst.inserted = c.inserted
+	dst.changed = c.changed
+	dst.copier = c.copier
+	dst.transform = c.transform
+	dst.limiter = c.limiter
+	dst.selector = c.selector
+	dst.candidates = c.candidates
+	dst.strict = c.strict
+	dst.decay = c.decay
+	dst.samples = c.samples
+	dst.stamp = c.stamp
+	dst.noPromote = c.noPromote
+	dst.mru = c.mru
+
+	if c.s3 != nil {
+		dst.s3 = c.s3.clone()
+	}
+
+	dst.sizer = c.sizer
+	dst.onHit = c.onHit
+	dst.metrics = c.metrics
+	dst.events = c.events
+	dst.trace = c.trace
+	dst.cost = c.cost
+	dst.inflation = c.inflation
+	dst.writeTTL = c.writeTTL
+	dst.idle = c.idle
+	dst.ghosts = newGhosts[K](cap(c.ghosts.keys))
+	dst.negatives = negatives[K]{ttl: c.negatives.ttl, matches: c.negatives.matches}
+	dst.filter = c.filter
+	dst.filter.bits = slices.Clone(c.filter.bits)
+	dst.promoteEvery = c.promoteEvery
+	dst.promoteBuffer = c.promoteBuffer
+	dst.refreshAhead = c.refreshAhead
+	dst.janitor = c.janitor
+	dst.unpinLeased(c.leases)
+
+	if c.copier != nil {
+		for i := range dst.vals {
+			dst.vals[i] = c.copier(dst.vals[i])
+		}
+	}
+}
+
+// Copy of the cache, including recency, expiry, pins, aliases and settings, but not stats. The
+// copy is thread-safe if the cache is.
+func (c *lru[K, V]) Clone() LRU[K, V] {
+	dst := new(lru[K, V])
+	c.cloneInto(dst)
+	return dst
+}
+
+// Add or overwrite all items in another cache, from least to most recently used and with their
+// remaining ttl, as with SetWithTTL.
+func (c *lru[K, V]) CopyTo(dst LRU[K, V]) {
+	// Collect the entries first, as dst may be the cache itself
+	for _, e := range slices.Collect(c.IterateEntries()) {
+		dst.SetWithTTL(e.Key, e.Value, e.TTL)
+	}
+}
+
+// Detached copy of the cache, including recency, expiry, pins and aliases. The copy isn't
+// thread-safe, and has no callbacks, metrics or stats.
+func (c *lru[K, V]) Snapshot() LRU[K, V] {
+	dst := new(lru[K, V])
+	c.cloneInto(dst)
+
+	dst.evicted = nil
+	dst.rejected = nil
+	dst.inserted = nil
+	dst.changed = nil
+	dst.onHit = nil
+	dst.metrics = nil
+	dst.events = nil
+	dst.trace = tracer[K]{}
+	dst.ghosts = ghosts[K]{}
+
+	return dst
+}
+
+// Clone implements LRU.
+func (t *threadsafe[K, V]) Clone() LRU[K, V] {
+	dst := new(threadsafe[K, V])
+
+	t.mu.RLock()
+	t.lru.cloneInto(&dst.lru)
+	t.mu.RUnlock()
+
+	// Unwrap the callbacks, which are wrapped again for the copy
+	dst.lru.evicted = t.evicted
+	dst.lru.rejected = t.rejected
+	dst.lru.changed = t.changed
+	dst.start()
+
+	return dst
+}
+
+// CopyTo implements LRU.
+func (t *threadsafe[K, V]) CopyTo(dst LRU[K, V]) {
+	// Entries are yielded without holding the lock, so the cache can copy into itself
+	for e := range t.IterateEntries() {
+		dst.SetWithTTL(e.Key, e.Value, e.TTL)
+	}
+}
+
+// Snapshot implements LRU.
+func (t *threadsafe[K, V]) Snapshot() LRU[K, V] {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.Snapshot()
+}
+
+// Collect all items under the read lock, and yield them once the lock is released. This makes
+// it safe to call the cache during iteration.
+func (t *threadsafe[K, V]) detached(seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.mu.RLock()
+
+		keys := make([]K, 0, len(t.lru.keys))
+		vals := make([]V, 0, len(t.lru.vals))
+
+		for k, v := range seq {
+			keys = append(keys, k)
+			vals = append(vals, v)
+		}
+
+		t.mu.RUnlock()
+
+		for i := range keys {
+			if !yield(keys[i], vals[i]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package lru
+
+import (
+	"cmp"
+	"slices"
+	"sync/atomic"
+)
+
+// Cumulative cache counters.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 // Evictions due to capacity
+}
+
+// Ratio of hits to all lookups, or zero if there has been no lookups.
+func (s Stats) HitRatio() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+
+	return 0
+}
+
+// A capacity estimate, as returned by Recommendation.
+type Recommendation struct {
+	Target   float64 // Target hit ratio
+	HitRatio float64 // Current hit ratio
+	Capacity int     // Estimated capacity needed to reach the target hit ratio
+
+	// Whether the target is reachable within the remembered evicted keys. If not, Capacity is a
+	// lower bound.
+	Reachable bool
+}
+
+// Cumulative hit, miss and eviction counters.
+func (c *lru[K, V]) Stats() Stats {
+	return c.stats.load()
+}
+
+// Estimate the capacity needed to reach a target hit ratio (0-1), based on hits, misses and
+// misses of recently evicted keys. Requires WithGhosts to look beyond the current capacity.
+func (c *lru[K, V]) Recommendation(target float64) (r Recommendation) {
+	stats := c.stats.load()
+
+	r.Target = target
+	r.HitRatio = stats.HitRatio()
+	r.Capacity = c.capacity
+
+	total := stats.Hits + stats.Misses
+
+	if total == 0 || r.HitRatio >= target {
+		r.Reachable = total > 0
+		return
+	}
+
+	// Every miss of a key evicted at depth d would have been a hit with d+1 more capacity.
+	hits := stats.Hits
+
+	for depth := range c.ghosts.hits {
+		hits += atomic.LoadUint64(&c.ghosts.hits[depth])
+
+		if float64(hits)/float64(total) >= target {
+			r.Capacity += depth + 1
+			r.Reachable = true
+			return
+		}
+	}
+
+	r.Capacity += len(c.ghosts.keys)
+	return
+}
+
+// Hits of a key, as returned by TopKeys.
+type KeyStat[K comparable] struct {
+	Key   K
+	Hits  uint64
+	Bytes int // Size reported by WithSizer, or zero without it
+}
+
+// The n unexpired keys with the most hits, ordered by hits and then by size.
+func (c *lru[K, V]) TopKeys(n int) []KeyStat[K] {
+	if n <= 0 {
+		return nil
+	}
+
+	top := make([]KeyStat[K], 0, len(c.keys))
+
+	for i := range c.keys {
+		if !c.alive(i) {
+			continue
+		}
+
+		s := KeyStat[K]{Key: c.keys[i], Hits: atomic.LoadUint64(&c.meta[i].hits)}
+
+		if c.sizer != nil {
+			s.Bytes = c.sizer(c.keys[i], c.vals[i])
+		}
+
+		top = append(top, s)
+	}
+
+	slices.SortFunc(top, func(a, b KeyStat[K]) int {
+		return cmp.Or(cmp.Compare(b.Hits, a.Hits), cmp.Compare(b.Bytes, a.Bytes))
+	})
+
+	return slices.Clip(top[:min(n, len(top))])
+}
+
+// Counters that are safe to update during concurrent reads.
+type counters struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+func (c *counters) load() Stats {
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+func (c *lru[K, V]) hit(key K) {
+	c.stats.hits.Add(1)
+	c.emit(EventHit, key)
+	c.traced(EventHit, key)
+
+	if c.metrics != nil {
+		c.metrics.Hit()
+	}
+}
+
+func (c *lru[K, V]) miss(key K) {
+	c.stats.misses.Add(1)
+	c.ghosts.miss(c.canonical(key))
+	c.emit(EventMiss, key)
+	c.traced(EventMiss, key)
+
+	if c.metrics != nil {
+		c.metrics.Miss()
+	}
+}
+
+func (c *lru[K, V]) evictedByCapacity(key K) {
+	c.stats.evictions.Add(1)
+	c.ghosts.push(key)
+	c.emit(EventEvict, key)
+
+	if c.metrics != nil {
+		c.metrics.Evict()
+	}
+}
+
+// Ring buffer of recently evicted keys.
+type ghosts[K comparable] struct {
+	keys []K
+	hits []uint64 // Misses per depth, where depth 0 is the most recently evicted key
+	next int
+}
+
+func newGhosts[K comparable](n int) ghosts[K] {
+	return ghosts[K]{
+		keys: make([]K, 0, n),
+		hits: make([]uint64, n),
+	}
+}
+
+func (g *ghosts[K]) push(key K) {
+	if cap(g.keys) == 0 {
+		return
+	}
+
+	if len(g.keys) < cap(g.keys) {
+		g.keys = append(g.keys, key)
+	} else {
+		g.keys[g.next] = key
+	}
+
+	g.next = (g.next + 1) % cap(g.keys)
+}
+
+func (g *ghosts[K]) miss(key K) {
+	l := len(g.keys)
+
+	for depth := range l {
+		if g.keys[(g.next-1-depth+l)%l] == key {
+			atomic.AddUint64(&g.hits[depth], 1)
+			return
+		}
+	}
+}
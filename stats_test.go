@@ -0,0 +1,63 @@
+package lru
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	cache := New[int, int](2)
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Get(1)
+	cache.Get(3)
+	cache.Set(3, 3)
+
+	if s := cache.Stats(); s != (Stats{Hits: 1, Misses: 1, Evictions: 1}) {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+}
+
+func TestRecommendation(t *testing.T) {
+	cache := NewWithOptions(4, WithGhosts[int, int](8))
+
+	// Cycle through 6 keys, which never hits with a capacity of 4
+	for range 10 {
+		for k := range 6 {
+			if _, ok := cache.Get(k); !ok {
+				cache.Set(k, k)
+			}
+		}
+	}
+
+	r := cache.Recommendation(0.5)
+
+	if !r.Reachable || r.Capacity != 6 {
+		t.Fatalf("expected a reachable capacity of 6, got %+v", r)
+	}
+
+	if r := cache.Recommendation(1); r.Reachable {
+		t.Fatalf("expected a perfect hit ratio to be unreachable, got %+v", r)
+	}
+}
+
+func TestTopKeys(t *testing.T) {
+	cache := NewWithOptions(4, WithSizer(func(key string, val []byte) int {
+		return len(val)
+	}))
+
+	cache.Set("a", make([]byte, 1))
+	cache.Set("b", make([]byte, 2))
+	cache.Set("c", make([]byte, 3))
+
+	for range 3 {
+		cache.Get("a")
+	}
+
+	top := cache.TopKeys(2)
+
+	if len(top) != 2 || top[0].Key != "a" || top[0].Hits != 3 || top[1].Key != "c" || top[1].Bytes != 3 {
+		t.Fatalf("unexpected top keys: %+v", top)
+	}
+
+	if top := cache.TopKeys(10); len(top) != 3 {
+		t.Fatalf("expected all 3 keys, got %+v", top)
+	}
+}
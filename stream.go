@@ -0,0 +1,234 @@
+package lru
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+// Codec encodes the items of a cache for Export and Import.
+type Codec[K comparable, V any] interface {
+	AppendEntry(dst []byte, key K, val V) ([]byte, error)
+	DecodeEntry(data []byte) (key K, val V, err error)
+}
+
+// JSONCodec encodes items as JSON.
+type JSONCodec[K comparable, V any] struct{}
+
+// AppendEntry implements Codec.
+func (JSONCodec[K, V]) AppendEntry(dst []byte, key K, val V) ([]byte, error) {
+	b, err := json.Marshal(jsonItem[K, V]{Key: key, Val: val})
+	return append(dst, b...), err
+}
+
+// DecodeEntry implements Codec.
+func (JSONCodec[K, V]) DecodeEntry(data []byte) (key K, val V, err error) {
+	var item jsonItem[K, V]
+	err = json.Unmarshal(data, &item)
+	return item.Key, item.Val, err
+}
+
+const (
+	streamMagic   = "LRUS"
+	streamVersion = 1
+	chunkSize     = 64 << 10 // Bytes of records per chunk, before flushing
+	maxChunkSize  = 64 << 20 // Bytes of a chunk that Import accepts
+)
+
+// Write all unexpired items of a cache to w, from least to most recently used, in a chunked
+// binary format: a version header, followed by chunks of length-prefixed records, each chunk
+// prefixed by its length and checksum, and ended by an empty chunk. Items are encoded by the
+// codec, and keep their expiry. A thread-safe cache is iterated from a snapshot, so all items are
+// held in memory while they're written, but without holding the lock.
+func Export[K comparable, V any](w io.Writer, cache LRU[K, V], codec Codec[K, V]) (err error) {
+	bw := bufio.NewWriter(w)
+
+	if _, err = bw.WriteString(streamMagic); err != nil {
+		return
+	}
+
+	if err = bw.WriteByte(streamVersion); err != nil {
+		return
+	}
+
+	var chunk, entry []byte
+
+	for e := range cache.IterateEntries() {
+		var expires int64
+
+		if !e.Expires.IsZero() {
+			expires = e.Expires.UnixNano()
+		}
+
+		if entry, err = codec.AppendEntry(entry[:0], e.Key, e.Value); err != nil {
+			return
+		}
+
+		chunk = binary.AppendVarint(chunk, expires)
+		chunk = binary.AppendUvarint(chunk, uint64(len(entry)))
+		chunk = append(chunk, entry...)
+
+		if len(chunk) >= chunkSize {
+			if err = writeChunk(bw, chunk); err != nil {
+				return
+			}
+
+			chunk = chunk[:0]
+		}
+	}
+
+	if len(chunk) > 0 {
+		if err = writeChunk(bw, chunk); err != nil {
+			return
+		}
+	}
+
+	// An empty chunk marks the end
+	if err = writeChunk(bw, nil); err != nil {
+		return
+	}
+
+	return bw.Flush()
+}
+
+// Current time by the clock of a cache, or the system clock for other implementations of LRU.
+func cacheNow[K comparable, V any](cache LRU[K, V]) time.Time {
+	switch c := cache.(type) {
+	case *lru[K, V]:
+		return c.clock.Now()
+	case *threadsafe[K, V]:
+		return c.lru.clock.Now()
+	}
+
+	return time.Now()
+}
+
+func writeChunk(w io.Writer, chunk []byte) (err error) {
+	var head [8]byte
+
+	binary.LittleEndian.PutUint32(head[:4], uint32(len(chunk)))
+	binary.LittleEndian.PutUint32(head[4:], crc32.ChecksumIEEE(chunk))
+
+	if _, err = w.Write(head[:]); err == nil {
+		_, err = w.Write(chunk)
+	}
+
+	return
+}
+
+// Read items written by Export into a cache, from least to most recently used, as with
+// SetWithTTL. Items that have expired meanwhile by the clock of the cache are skipped. A chunk that fails its checksum or
+// the codec aborts the import, unless skipCorrupt is set, in which case the chunk is skipped and
+// the import resumes with the next one. Returns the number of imported items and skipped chunks.
+func Import[K comparable, V any](r io.Reader, cache LRU[K, V], codec Codec[K, V], skipCorrupt bool) (imported, skipped int, err error) {
+	br := bufio.NewReader(r)
+
+	var head [8]byte
+
+	if _, err = io.ReadFull(br, head[:len(streamMagic)+1]); err != nil {
+		return
+	}
+
+	if string(head[:len(streamMagic)]) != streamMagic {
+		return 0, 0, errors.New("lru: not an exported cache")
+	}
+
+	if v := head[len(streamMagic)]; v != streamVersion {
+		return 0, 0, fmt.Errorf("lru: unsupported export version %d", v)
+	}
+
+	var chunk []byte
+
+	for {
+		if _, err = io.ReadFull(br, head[:]); err != nil {
+			return
+		}
+
+		size := binary.LittleEndian.Uint32(head[:4])
+
+		if size == 0 {
+			return
+		}
+
+		if size > maxChunkSize {
+			return imported, skipped, fmt.Errorf("lru: chunk of %d bytes exceeds the maximum", size)
+		}
+
+		if cap(chunk) < int(size) {
+			chunk = make([]byte, size)
+		}
+
+		chunk = chunk[:size]
+
+		if _, err = io.ReadFull(br, chunk); err != nil {
+			return
+		}
+
+		if crc32.ChecksumIEEE(chunk) != binary.LittleEndian.Uint32(head[4:]) {
+			err = errors.New("lru: corrupt chunk")
+		} else {
+			err = importChunk(chunk, cache, codec, &imported)
+		}
+
+		if err != nil {
+			if !skipCorrupt {
+				return
+			}
+
+			skipped++
+			err = nil
+		}
+	}
+}
+
+// Decode a whole chunk before importing it, so that a corrupt chunk is skipped as a whole.
+func importChunk[K comparable, V any](chunk []byte, cache LRU[K, V], codec Codec[K, V], imported *int) error {
+	var entries []EntryInfo[K, V]
+
+	now := cacheNow(cache)
+
+	for len(chunk) > 0 {
+		expires, n := binary.Varint(chunk)
+
+		if n <= 0 {
+			return errors.New("lru: corrupt record")
+		}
+
+		chunk = chunk[n:]
+		size, n := binary.Uvarint(chunk)
+
+		if n <= 0 || size > uint64(len(chunk)-n) {
+			return errors.New("lru: corrupt record")
+		}
+
+		data := chunk[n : n+int(size)]
+		chunk = chunk[n+int(size):]
+		key, val, err := codec.DecodeEntry(data)
+
+		if err != nil {
+			return err
+		}
+
+		var ttl time.Duration
+
+		if expires != 0 {
+			if ttl = time.Unix(0, expires).Sub(now); ttl <= 0 {
+				continue
+			}
+		}
+
+		entries = append(entries, EntryInfo[K, V]{Key: key, Value: val, TTL: ttl})
+	}
+
+	for _, e := range entries {
+		cache.SetWithTTL(e.Key, e.Value, e.TTL)
+	}
+
+	*imported += len(entries)
+	return nil
+}
@@ -0,0 +1,76 @@
+package lru
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExportImport(t *testing.T) {
+	src := NewThreadSafe[string, int](5000)
+
+	for i := range 5000 {
+		src.Set(fmt.Sprint(i), i)
+	}
+
+	src.SetWithTTL("9", 9, time.Hour)
+
+	var buf bytes.Buffer
+
+	if err := Export(&buf, src, JSONCodec[string, int]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := New[string, int](5000)
+
+	if n, skipped, err := Import(bytes.NewReader(buf.Bytes()), dst, JSONCodec[string, int]{}, false); err != nil || n != 5000 || skipped != 0 {
+		t.Fatalf("expected all items to be imported, got %d and %v", n, err)
+	}
+
+	if !slices.Equal(dst.Keys(), src.Keys()) {
+		t.Fatal("expected the recency order to be kept")
+	}
+
+	if ttl, _ := dst.TTL("9"); ttl <= 59*time.Minute {
+		t.Fatalf("expected the expiry to be kept, got %v", ttl)
+	}
+
+	// Corrupt a record of the first chunk
+	data := buf.Bytes()
+	data[20] ^= 0xff
+
+	if _, _, err := Import(bytes.NewReader(data), New[string, int](5000), JSONCodec[string, int]{}, false); err == nil {
+		t.Fatal("expected a corrupt chunk to abort the import")
+	}
+
+	dst = New[string, int](5000)
+	n, skipped, err := Import(bytes.NewReader(data), dst, JSONCodec[string, int]{}, true)
+
+	if err != nil || skipped != 1 || n == 0 || n >= 5000 || dst.Has("0") {
+		t.Fatalf("expected only the corrupt chunk to be skipped, got %d items, %d skipped and %v", n, skipped, err)
+	}
+}
+
+func TestExportImportClock(t *testing.T) {
+	clock := newFakeClock()
+	src := NewWithOptions(8, WithClock[int, int](clock))
+	src.SetWithTTL(1, 1, time.Hour)
+
+	var buf bytes.Buffer
+
+	if err := Export(&buf, src, JSONCodec[int, int]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := NewThreadSafeWithOptions(8, WithClock[int, int](clock))
+
+	if n, _, err := Import(&buf, dst, JSONCodec[int, int]{}, false); err != nil || n != 1 {
+		t.Fatalf("expected the item to be imported by the clock of the cache, got %d and %v", n, err)
+	}
+
+	if ttl, ok := dst.TTL(1); !ok || ttl != time.Hour {
+		t.Fatalf("expected the remaining ttl to be kept, got %v", ttl)
+	}
+}
@@ -0,0 +1,93 @@
+package lru
+
+import (
+	"iter"
+	"strings"
+	"unsafe"
+)
+
+// Get an item of a string-keyed cache by a byte slice key, without allocating a string. Same as
+// Get(string(key)) otherwise.
+func GetBytes[V any](c LRU[string, V], key []byte, opts ...CallOption) (val V, ok bool) {
+	if retainsKeys(c) {
+		return c.Get(string(key), opts...)
+	}
+
+	// The key must not outlive the call, as it shares memory with the byte slice
+	return c.Get(unsafe.String(unsafe.SliceData(key), len(key)), opts...)
+}
+
+// Whether a Get might retain its key argument beyond the call, e.g. in an event, a trace or a
+// key transform that interns keys.
+func retainsKeys[V any](c LRU[string, V]) bool {
+	switch c := c.(type) {
+	case *lru[string, V]:
+		return c.events != nil || c.onHit != nil || c.trace.record != nil || c.transform != nil
+	case *threadsafe[string, V]:
+		return c.lru.events != nil || c.lru.onHit != nil || c.lru.trace.record != nil || c.lru.transform != nil
+	}
+
+	return true
+}
+
+// Remove every item of a string-keyed cache whose key has the prefix, and notify each evict.
+// Returns the number of removed items.
+func RemovePrefix[V any](c LRU[string, V], prefix string) (removed int) {
+	return c.RemoveFunc(func(key string, _ V) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// Namespace is a view of a string-keyed cache, where all keys are prefixed. Namespaces share the
+// capacity of the cache, but can be cleared separately, e.g. per tenant.
+type Namespace[V any] struct {
+	c      LRU[string, V]
+	prefix string
+}
+
+// Create a view of a cache, where all keys are prefixed.
+func NewNamespace[V any](c LRU[string, V], prefix string) Namespace[V] {
+	return Namespace[V]{c: c, prefix: prefix}
+}
+
+func (n Namespace[V]) Has(key string) bool {
+	return n.c.Has(n.prefix + key)
+}
+
+func (n Namespace[V]) Get(key string, opts ...CallOption) (val V, ok bool) {
+	return n.c.Get(n.prefix+key, opts...)
+}
+
+func (n Namespace[V]) GetOrSet(key string, setter func(string) (V, error), opts ...CallOption) (val V, err error) {
+	return n.c.GetOrSet(n.prefix+key, func(string) (V, error) {
+		return setter(key)
+	}, opts...)
+}
+
+func (n Namespace[V]) Set(key string, val V) (ok bool) {
+	return n.c.Set(n.prefix+key, val)
+}
+
+func (n Namespace[V]) Replace(key string, val V) (existed bool) {
+	return n.c.Replace(n.prefix+key, val)
+}
+
+func (n Namespace[V]) Remove(key string) (existed bool) {
+	return n.c.Remove(n.prefix + key)
+}
+
+// Iterate all items of the namespace in no particular order, with their keys unprefixed.
+func (n Namespace[V]) Iterate() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		for k, v := range n.c.Iterate() {
+			if key, ok := strings.CutPrefix(k, n.prefix); ok && !yield(key, v) {
+				return
+			}
+		}
+	}
+}
+
+// Remove all items of the namespace, and notify each evict. Returns the number of removed items.
+func (n Namespace[V]) Clear() (removed int) {
+	return RemovePrefix(n.c, n.prefix)
+}
@@ -0,0 +1,74 @@
+package lru
+
+import "testing"
+
+func TestGetBytes(t *testing.T) {
+	cache := NewThreadSafe[string, int](8)
+	cache.Set("foo", 1)
+	key := []byte("foo")
+
+	if v, ok := GetBytes(cache, key); !ok || v != 1 {
+		t.Fatalf("expected a hit, got %d", v)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { GetBytes(cache, key) }); allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestGetBytesTrace(t *testing.T) {
+	ring := NewTraceRing[string](8)
+	cache := NewThreadSafeWithOptions(8, WithTrace[string, int](ring.Record, 1))
+	key := []byte("abc")
+
+	GetBytes(cache, key)
+	copy(key, "xyz")
+
+	if events := ring.Events(); len(events) != 1 || events[0].Key != "abc" {
+		t.Fatalf("expected the traced key to not share the reused buffer, got %v", events)
+	}
+}
+
+func TestGetBytesTransform(t *testing.T) {
+	interned := make(map[string]string)
+	cache := NewWithOptions(8, WithKeyTransform[string, int](func(key string) string {
+		if s, ok := interned[key]; ok {
+			return s
+		}
+
+		interned[key] = key
+		return key
+	}))
+
+	key := []byte("abc")
+	GetBytes(cache, key)
+	copy(key, "xyz")
+
+	if _, ok := interned["abc"]; !ok {
+		t.Fatalf("expected the interned key to not share the reused buffer, got %v", interned)
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	cache := NewThreadSafe[string, int](8)
+	a := NewNamespace(cache, "a:")
+	b := NewNamespace(cache, "b:")
+
+	a.Set("x", 1)
+	a.Set("y", 2)
+	b.Set("x", 3)
+
+	if v, _ := b.Get("x"); v != 3 || !cache.Has("a:x") {
+		t.Fatalf("expected namespaced keys, got %d", v)
+	}
+
+	if n := a.Clear(); n != 2 || a.Has("x") || !b.Has("x") {
+		t.Fatalf("expected only the namespace to be cleared, got %d removed", n)
+	}
+
+	for k := range b.Iterate() {
+		if k != "x" {
+			t.Fatalf("expected unprefixed keys, got %q", k)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package lru
+
+import "sync"
+
+// Capacity of a zero Map.
+const DefaultMapCapacity = 1024
+
+// Map is a bounded drop-in for sync.Map, backed by a thread-safe cache. Code using sync.Map can
+// switch by replacing the declaration with a Map[any, any], or with concrete types, as the zero
+// Map is ready to use with a capacity of DefaultMapCapacity. Unlike sync.Map, items are evicted in
+// least recently used order once the capacity is reached. A Map must not be copied after first
+// use.
+type Map[K comparable, V any] struct {
+	once sync.Once
+	c    LRU[K, V]
+}
+
+// Create a bounded sync.Map of at most capacity items.
+func NewMap[K comparable, V any](capacity int, opts ...Option[K, V]) *Map[K, V] {
+	return &Map[K, V]{c: NewThreadSafeWithOptions(capacity, opts...)}
+}
+
+// The cache of the map, which a zero Map creates on first use.
+func (m *Map[K, V]) cache() LRU[K, V] {
+	m.once.Do(func() {
+		if m.c == nil {
+			m.c = NewThreadSafe[K, V](DefaultMapCapacity)
+		}
+	})
+
+	return m.c
+}
+
+// Value of a key, and mark it as most recently used.
+func (m *Map[K, V]) Load(key K) (value V, ok bool) {
+	return m.cache().Get(key)
+}
+
+// Add or overwrite the value of a key.
+func (m *Map[K, V]) Store(key K, value V) {
+	m.cache().Upsert(key, value)
+}
+
+// Existing value of a key if any, or else store the provided value. Loaded reports whether the
+// value was found.
+func (m *Map[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	return m.cache().GetOrSetValue(key, value)
+}
+
+// Delete a key, and return its previous value if any.
+func (m *Map[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	return m.cache().GetAndRemove(key)
+}
+
+// Delete a key, if it exists.
+func (m *Map[K, V]) Delete(key K) {
+	m.cache().Remove(key)
+}
+
+// Store the value of a key, and return its previous value if any.
+func (m *Map[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	return m.cache().Swap(key, value)
+}
+
+// Call f for each item, until f returns false. Ranges a snapshot, so f may call the map.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	for k, v := range m.cache().Iterate() {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+// Delete all items.
+func (m *Map[K, V]) Clear() {
+	m.cache().RemoveAll()
+}
+
+// Underlying cache, e.g. for Len or Stats.
+func (m *Map[K, V]) Cache() LRU[K, V] {
+	return m.cache()
+}
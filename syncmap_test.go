@@ -0,0 +1,53 @@
+package lru
+
+import "testing"
+
+func TestMap(t *testing.T) {
+	m := NewMap[any, any](2)
+
+	m.Store("a", 1)
+
+	if v, loaded := m.LoadOrStore("a", 2); !loaded || v != 1 {
+		t.Fatalf("expected the stored value, got %v", v)
+	}
+
+	if v, loaded := m.Swap("a", 3); !loaded || v != 1 {
+		t.Fatalf("expected the previous value, got %v", v)
+	}
+
+	m.Store("b", 1)
+	m.Load("a")
+	m.Store("c", 1)
+
+	if _, ok := m.Load("b"); ok {
+		t.Fatal("expected the least recently used key to be evicted")
+	}
+
+	// Range may use the map, as with sync.Map
+	m.Range(func(key, _ any) bool {
+		m.Delete(key)
+		return true
+	})
+
+	if m.Cache().Len() != 0 {
+		t.Fatalf("expected all keys to be deleted, got %d", m.Cache().Len())
+	}
+
+	if _, loaded := m.LoadAndDelete("a"); loaded {
+		t.Fatal("expected a deleted key to be missing")
+	}
+}
+
+func TestMapZero(t *testing.T) {
+	var m Map[string, int]
+
+	m.Store("a", 1)
+
+	if v, ok := m.Load("a"); !ok || v != 1 {
+		t.Fatalf("expected a zero map to be usable, got %d", v)
+	}
+
+	if c := m.Cache().Cap(); c != DefaultMapCapacity {
+		t.Fatalf("expected the default capacity, got %d", c)
+	}
+}
@@ -0,0 +1,96 @@
+package lru
+
+import (
+	"maps"
+	"slices"
+)
+
+// Same as Set, but also tags the new item, so that it can be removed together with other items
+// of a tag by InvalidateTag. Tags are removed together with their item.
+func (c *lru[K, V]) SetTagged(key K, val V, tags ...string) (ok bool) {
+	if ok = c.Set(key, val); ok && len(tags) > 0 {
+		c.tag(c.canonical(key), tags)
+	}
+
+	return
+}
+
+// Remove every item of a tag, and notify each evict. Returns the number of removed items.
+func (c *lru[K, V]) InvalidateTag(tag string) (removed int) {
+	for key := range c.tags[tag] {
+		if c.removeKey(key) {
+			c.change(key)
+			removed++
+		}
+	}
+
+	return
+}
+
+func (c *lru[K, V]) tag(key K, tags []string) {
+	if c.tags == nil {
+		c.tags = make(map[string]map[K]struct{})
+		c.tagsOf = make(map[K][]string)
+	}
+
+	for _, tag := range tags {
+		keys, ok := c.tags[tag]
+
+		if !ok {
+			keys = make(map[K]struct{})
+			c.tags[tag] = keys
+		}
+
+		if _, ok = keys[key]; !ok {
+			keys[key] = struct{}{}
+			c.tagsOf[key] = append(c.tagsOf[key], tag)
+		}
+	}
+}
+
+func (c *lru[K, V]) untagAll(key K) {
+	for _, tag := range c.tagsOf[key] {
+		delete(c.tags[tag], key)
+
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+
+	delete(c.tagsOf, key)
+}
+
+func (c *lru[K, V]) cloneTags() (tags map[string]map[K]struct{}, tagsOf map[K][]string) {
+	if c.tags == nil {
+		return
+	}
+
+	tags = make(map[string]map[K]struct{}, len(c.tags))
+	tagsOf = make(map[K][]string, len(c.tagsOf))
+
+	for tag, keys := range c.tags {
+		tags[tag] = maps.Clone(keys)
+	}
+
+	for key, keyTags := range c.tagsOf {
+		tagsOf[key] = slices.Clone(keyTags)
+	}
+
+	return
+}
+
+// SetTagged implements LRU.
+func (t *threadsafe[K, V]) SetTagged(key K, val V, tags ...string) (ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.SetTagged(key, val, tags...)
+}
+
+// InvalidateTag implements LRU.
+func (t *threadsafe[K, V]) InvalidateTag(tag string) (removed int) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.InvalidateTag(tag)
+}
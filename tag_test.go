@@ -0,0 +1,73 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInvalidateTag(t *testing.T) {
+	var evicted []int
+
+	cache := NewThreadSafe(4, func(k int, _ int) {
+		evicted = append(evicted, k)
+	})
+
+	cache.SetTagged(1, 1, "row:1")
+	cache.SetTagged(2, 2, "row:1", "row:2")
+	cache.SetTagged(3, 3, "row:2")
+	cache.Set(4, 4)
+
+	if cache.SetTagged(1, 10, "row:3") {
+		t.Fatal("expected an existing item to be left untouched")
+	}
+
+	if n := cache.InvalidateTag("row:1"); n != 2 || cache.Has(1) || cache.Has(2) || len(evicted) != 2 {
+		t.Fatalf("expected both tagged items to be removed, got %d", n)
+	}
+
+	// The removed item is no longer tagged
+	cache.Set(2, 2)
+
+	if n := cache.InvalidateTag("row:2"); n != 1 || !cache.Has(2) || cache.Has(3) {
+		t.Fatalf("expected only the remaining tagged item to be removed, got %d", n)
+	}
+
+	if n := cache.InvalidateTag("row:3"); n != 0 || cache.Len() != 2 {
+		t.Fatalf("expected an unknown tag to remove nothing, got %d", n)
+	}
+}
+
+func TestTagExpired(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewWithOptions(4, WithClock[int, int](clock), WithExpireAfterWrite[int, int](time.Minute))
+
+	cache.SetTagged(1, 1, "old")
+	clock.Advance(time.Hour)
+	cache.SetTagged(1, 2, "new")
+
+	if n := cache.InvalidateTag("old"); n != 0 || !cache.Has(1) {
+		t.Fatalf("expected an overwritten expired item to lose its tags, got %d", n)
+	}
+
+	if n := cache.InvalidateTag("new"); n != 1 {
+		t.Fatalf("expected the new tags to be kept, got %d", n)
+	}
+}
+
+func TestTagClone(t *testing.T) {
+	cache := New[int, int](4)
+	cache.SetTagged(1, 1, "a", "b", "c")
+
+	// Tags appended to either cache mustn't show up in the other
+	clone := cache.Clone()
+	cache.(*lru[int, int]).tag(1, []string{"d"})
+	clone.(*lru[int, int]).tag(1, []string{"e"})
+
+	if err := cache.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := clone.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
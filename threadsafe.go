@@ -1,30 +1,79 @@
 package lru
 
 import (
+	"fmt"
 	"iter"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var _ LRU[struct{}, struct{}] = (*threadsafe[struct{}, struct{}])(nil)
 
 type threadsafe[K comparable, V any] struct {
-	lru lru[K, V]
-	mu  sync.RWMutex
+	lru      lru[K, V]
+	mu       sync.RWMutex
+	reads    atomic.Uint64
+	promoted []int // Indices of hits under the read lock, promoted once the write lock is held
+	pending  atomic.Int64
+
+	// Keys being refreshed ahead of expiry, guarded by the write lock
+	refreshing map[K]struct{}
+	refreshes  sync.WaitGroup
+
+	// Evicted, rejected and changed items awaiting notification once the write lock is released, guarded
+	// by the write lock. This lets the callbacks take their time, and call the cache.
+	evicted  func(K, V)
+	rejected func(K, V)
+	changed  func(K)
+	queue    []notification[K, V]
+
+	// Janitor lifecycle, or nil without a janitor
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
+// Create a thread-safe cache. Iterators yield a copy of the items after releasing the lock, and
+// evict, reject and change callbacks are called after releasing the lock, so the cache can be used
+// during iteration and from these callbacks. Other callbacks are called while holding the lock, and
+// must not call the cache.
 func NewThreadSafe[K comparable, V any](capacity int, evicted ...func(key K, val V)) LRU[K, V] {
-	c := lru[K, V]{
-		keys:    make([]K, 0, capacity),
-		vals:    make([]V, 0, capacity),
-		lastUse: make([]uint64, 0, capacity),
+	return NewThreadSafeWithOptions(capacity, evictedOption(evicted)...)
+}
+
+// Wrap the callbacks and start any background goroutines of an initialized cache.
+func (t *threadsafe[K, V]) start() {
+	if t.lru.evicted != nil {
+		t.evicted = t.lru.evicted
+		t.lru.evicted = t.queueEvicted
+	}
+
+	if t.lru.rejected != nil {
+		t.rejected = t.lru.rejected
+		t.lru.rejected = t.queueRejected
 	}
 
-	if len(evicted) > 0 {
-		c.evicted = evicted[0]
+	if t.lru.changed != nil {
+		t.changed = t.lru.changed
+		t.lru.changed = t.queueChanged
 	}
 
-	return &threadsafe[K, V]{
-		lru: c,
+	if release := t.lru.releaser; release != nil {
+		t.lru.releaser = func(val V) {
+			t.queue = append(t.queue, notification[K, V]{fn: func(_ K, val V) { release(val) }, val: val})
+		}
+	}
+
+	if t.lru.promoteBuffer > 0 {
+		t.promoted = make([]int, t.lru.promoteBuffer)
+	}
+
+	if t.lru.janitor > 0 {
+		t.stop = make(chan struct{})
+		t.done = make(chan struct{})
+		go t.sweep(t.lru.janitor)
 	}
 }
 
@@ -36,20 +85,172 @@ func (t *threadsafe[K, V]) Cap() int {
 	return t.lru.Cap()
 }
 
-// Get implements LRU.
-func (t *threadsafe[K, V]) Get(key K) (val V, ok bool) {
+// Available implements LRU.
+func (t *threadsafe[K, V]) Available() int {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	return t.lru.Get(key)
+	return t.lru.Available()
 }
 
-// GetOrSet implements LRU.
-func (t *threadsafe[K, V]) GetOrSet(key K, setter func(K) (V, error)) (val V, err error) {
+// Get implements LRU. As a hit mutates the recency order, it takes the write lock unless
+// promotions are buffered or sampled.
+func (t *threadsafe[K, V]) Get(key K, opts ...CallOption) (val V, ok bool) {
+	// Gets that never promote only need the read lock
+	if t.lru.noPromote&PromoteOnGet != 0 {
+		return t.getSampled(key, opts)
+	}
+
+	if t.promoted != nil {
+		return t.getBuffered(key, opts)
+	}
+
+	if t.lru.promoteEvery > 0 {
+		return t.getSampled(key, opts)
+	}
+
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Get(key, opts...)
+}
+
+// Look up an item under the read lock, and only take the write lock for sampled promotions.
+func (t *threadsafe[K, V]) getSampled(key K, opts []CallOption) (val V, ok bool) {
+	if callFlags(opts) != 0 {
+		return
+	}
+
+	t.mu.RLock()
+	idx, ok := t.lru.lookup(key)
+
+	if ok {
+		val = t.lru.copy(t.lru.vals[idx])
+	}
+
+	t.mu.RUnlock()
+
+	if ok && t.lru.noPromote&PromoteOnGet == 0 && t.reads.Add(1)%t.lru.promoteEvery == 0 {
+		t.Touch(key)
+	}
+
+	return
+}
+
+// Look up an item under the read lock, and buffer its promotion until the write lock is held.
+// If the buffer is full, the promotion is dropped.
+func (t *threadsafe[K, V]) getBuffered(key K, opts []CallOption) (val V, ok bool) {
+	if callFlags(opts) != 0 {
+		return
+	}
+
+	var full bool
+
+	t.mu.RLock()
+	idx, ok := t.lru.lookup(key)
+
+	if ok {
+		val = t.lru.copy(t.lru.vals[idx])
+
+		// Each reader gets a unique slot, which stays valid until the next write lock
+		if pos := t.pending.Add(1) - 1; pos < int64(len(t.promoted)) {
+			t.promoted[pos] = idx
+			full = pos == int64(len(t.promoted)-1)
+		}
+	}
+
+	t.mu.RUnlock()
+
+	if full {
+		t.lock()
+		t.unlock()
+	}
+
+	return
+}
+
+type notification[K comparable, V any] struct {
+	fn  func(K, V)
+	key K
+	val V
+}
+
+// Release the write lock, and notify any items evicted or rejected while it was held.
+func (t *threadsafe[K, V]) unlock() {
+	queue := t.queue
+	t.queue = nil
+	t.mu.Unlock()
+
+	for _, n := range queue {
+		n.fn(n.key, n.val)
+	}
+}
+
+// Queue an evicted item for notification once the write lock is released.
+func (t *threadsafe[K, V]) queueEvicted(key K, val V) {
+	t.queue = append(t.queue, notification[K, V]{fn: t.evicted, key: key, val: val})
+}
+
+// Queue a rejected item for notification once the write lock is released.
+func (t *threadsafe[K, V]) queueRejected(key K, val V) {
+	t.queue = append(t.queue, notification[K, V]{fn: t.rejected, key: key, val: val})
+}
+
+// Take the write lock, and apply any buffered promotions.
+func (t *threadsafe[K, V]) lock() {
 	t.mu.Lock()
-	defer t.mu.Unlock()
 
-	return t.lru.GetOrSet(key, setter)
+	if t.promoted == nil {
+		return
+	}
+
+	n := min(t.pending.Load(), int64(len(t.promoted)))
+
+	for _, idx := range t.promoted[:n] {
+		t.lru.promote(idx)
+	}
+
+	t.pending.Store(0)
+}
+
+// GetOrSet implements LRU.
+func (t *threadsafe[K, V]) GetOrSet(key K, setter func(K) (V, error), opts ...CallOption) (val V, err error) {
+	return t.GetOrSetTTL(key, 0, setter, opts...)
+}
+
+// GetOrSetTTL implements LRU.
+func (t *threadsafe[K, V]) GetOrSetTTL(key K, ttl time.Duration, setter func(K) (V, error), opts ...CallOption) (val V, err error) {
+	t.lock()
+	defer t.unlock()
+
+	if t.lru.refreshAhead > 0 && len(opts) == 0 {
+		t.refresh(key, func(key K) (val V, _ time.Duration, err error) {
+			val, err = setter(key)
+			return val, ttl, err
+		})
+	}
+
+	return t.lru.GetOrSetTTL(key, ttl, setter, opts...)
+}
+
+// GetOrSetExpiring implements LRU.
+func (t *threadsafe[K, V]) GetOrSetExpiring(key K, setter func(K) (V, time.Duration, error), opts ...CallOption) (val V, err error) {
+	t.lock()
+	defer t.unlock()
+
+	if t.lru.refreshAhead > 0 && len(opts) == 0 {
+		t.refresh(key, setter)
+	}
+
+	return t.lru.GetOrSetExpiring(key, setter, opts...)
+}
+
+// GetOrSetValue implements LRU.
+func (t *threadsafe[K, V]) GetOrSetValue(key K, val V) (actual V, loaded bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.GetOrSetValue(key, val)
 }
 
 // Has implements LRU.
@@ -60,60 +261,211 @@ func (t *threadsafe[K, V]) Has(key K) (ok bool) {
 	return t.lru.Has(key)
 }
 
+// Touch implements LRU.
+func (t *threadsafe[K, V]) Touch(key K) (ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Touch(key)
+}
+
 // Iterate all items in no particular order.
 func (t *threadsafe[K, V]) Iterate() iter.Seq2[K, V] {
-	return func(yield func(K, V) bool) {
-		t.mu.RLock()
-		defer t.mu.RUnlock()
-
-		for i := range t.lru.keys {
-			if !yield(t.lru.keys[i], t.lru.vals[i]) {
-				return
-			}
-		}
-	}
+	return t.detached(t.lru.Iterate())
 }
 
 // Iterate all items in ascending order.
 func (t *threadsafe[K, V]) IterateAsc() iter.Seq2[K, V] {
-	return func(yield func(K, V) bool) {
-		t.mu.RLock()
-		defer t.mu.RUnlock()
+	return t.detached(t.lru.IterateAsc())
+}
+
+// Iterate all items in descending order.
+func (t *threadsafe[K, V]) IterateDesc() iter.Seq2[K, V] {
+	return t.detached(t.lru.IterateDesc())
+}
 
-		tick := t.lru.oldestTick()
+// IterateN implements LRU.
+func (t *threadsafe[K, V]) IterateN(n int) iter.Seq2[K, V] {
+	return t.detached(t.lru.IterateN(n))
+}
 
-		for range t.lru.keys {
-			idx, ok := t.lru.find(tick)
+// IterateWhere implements LRU.
+func (t *threadsafe[K, V]) IterateWhere(pred func(K, V) bool) iter.Seq2[K, V] {
+	return t.detached(t.lru.IterateWhere(pred))
+}
 
-			if !ok || !yield(t.lru.keys[idx], t.lru.vals[idx]) {
-				return
-			}
+// Oldest implements LRU.
+func (t *threadsafe[K, V]) Oldest() (key K, val V, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 
-			tick++
-		}
-	}
+	return t.lru.Oldest()
 }
 
-// Iterate all items in descending order.
-func (t *threadsafe[K, V]) IterateDesc() iter.Seq2[K, V] {
-	return func(yield func(K, V) bool) {
-		t.mu.RLock()
-		defer t.mu.RUnlock()
+// Newest implements LRU.
+func (t *threadsafe[K, V]) Newest() (key K, val V, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.Newest()
+}
+
+// Rank implements LRU.
+func (t *threadsafe[K, V]) Rank(key K) (pos int, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.Rank(key)
+}
+
+// RemoveOldest implements LRU.
+func (t *threadsafe[K, V]) RemoveOldest() (key K, val V, ok bool) {
+	t.lock()
+	defer t.unlock()
 
-		tick := t.lru.tick - 1
+	return t.lru.RemoveOldest()
+}
 
-		for range t.lru.keys {
-			idx, ok := t.lru.find(tick)
+// RemoveNewest implements LRU.
+func (t *threadsafe[K, V]) RemoveNewest() (key K, val V, ok bool) {
+	t.lock()
+	defer t.unlock()
 
-			if !ok || !yield(t.lru.keys[idx], t.lru.vals[idx]) {
+	return t.lru.RemoveNewest()
+}
+
+// SetWithTTL implements LRU.
+func (t *threadsafe[K, V]) SetWithTTL(key K, val V, ttl time.Duration) (existed bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.SetWithTTL(key, val, ttl)
+}
+
+// ExpireAt implements LRU.
+func (t *threadsafe[K, V]) ExpireAt(key K, tm time.Time) (ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.ExpireAt(key, tm)
+}
+
+// TTL implements LRU.
+func (t *threadsafe[K, V]) TTL(key K) (ttl time.Duration, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.TTL(key)
+}
+
+// PurgeExpired implements LRU.
+func (t *threadsafe[K, V]) PurgeExpired() (removed int) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.PurgeExpired()
+}
+
+// IterateExpired implements LRU.
+func (t *threadsafe[K, V]) IterateExpired() iter.Seq2[K, V] {
+	return t.detached(t.lru.IterateExpired())
+}
+
+// Alias implements LRU.
+func (t *threadsafe[K, V]) Alias(alias K, primary K) (ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Alias(alias, primary)
+}
+
+// Unalias implements LRU.
+func (t *threadsafe[K, V]) Unalias(alias K) (existed bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Unalias(alias)
+}
+
+// Pin implements LRU.
+func (t *threadsafe[K, V]) Pin(key K) (ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Pin(key)
+}
+
+// Unpin implements LRU.
+func (t *threadsafe[K, V]) Unpin(key K) (ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Unpin(key)
+}
+
+// Entry implements LRU.
+func (t *threadsafe[K, V]) Entry(key K) (info EntryInfo[K, V], ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.Entry(key)
+}
+
+// IterateEntries implements LRU.
+func (t *threadsafe[K, V]) IterateEntries() iter.Seq[EntryInfo[K, V]] {
+	return func(yield func(EntryInfo[K, V]) bool) {
+		t.mu.RLock()
+		entries := slices.Collect(t.lru.IterateEntries())
+		t.mu.RUnlock()
+
+		for _, e := range entries {
+			if !yield(e) {
 				return
 			}
-
-			tick--
 		}
 	}
 }
 
+// Stats implements LRU.
+func (t *threadsafe[K, V]) Stats() Stats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.Stats()
+}
+
+// Recommendation implements LRU.
+func (t *threadsafe[K, V]) Recommendation(target float64) Recommendation {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.Recommendation(target)
+}
+
+// TopKeys implements LRU.
+func (t *threadsafe[K, V]) TopKeys(n int) []KeyStat[K] {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.TopKeys(n)
+}
+
+// Keys implements LRU.
+func (t *threadsafe[K, V]) Keys() []K {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.Keys()
+}
+
+// Values implements LRU.
+func (t *threadsafe[K, V]) Values() []V {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.Values()
+}
+
 // Len implements LRU.
 func (t *threadsafe[K, V]) Len() int {
 	t.mu.RLock()
@@ -122,50 +474,179 @@ func (t *threadsafe[K, V]) Len() int {
 	return t.lru.Len()
 }
 
+// Swap implements LRU.
+func (t *threadsafe[K, V]) Swap(key K, val V) (old V, existed bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Swap(key, val)
+}
+
+// GetAndRemove implements LRU.
+func (t *threadsafe[K, V]) GetAndRemove(key K) (val V, ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.GetAndRemove(key)
+}
+
 // Remove implements LRU.
 func (t *threadsafe[K, V]) Remove(key K) (existed bool) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+	t.lock()
+	defer t.unlock()
 
 	return t.lru.Remove(key)
 }
 
+// Warm implements LRU.
+func (t *threadsafe[K, V]) Warm(seq iter.Seq2[K, V]) {
+	t.lock()
+	defer t.unlock()
+
+	t.lru.Warm(seq)
+}
+
+// RemoveFunc implements LRU.
+func (t *threadsafe[K, V]) RemoveFunc(pred func(K, V) bool) (removed int) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.RemoveFunc(pred)
+}
+
 // RemoveAll implements LRU.
 func (t *threadsafe[K, V]) RemoveAll() {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+	t.lock()
+	defer t.unlock()
 
 	t.lru.RemoveAll()
 }
 
+// SetEvict implements LRU.
+func (t *threadsafe[K, V]) SetEvict(key K, val V) (evictedKey K, evictedVal V, evicted bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.SetEvict(key, val)
+}
+
+// ReplaceEvict implements LRU.
+func (t *threadsafe[K, V]) ReplaceEvict(key K, val V) (evictedKey K, evictedVal V, evicted bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.ReplaceEvict(key, val)
+}
+
+// Add implements LRU.
+func (t *threadsafe[K, V]) Add(key K, val V) (evicted bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Add(key, val)
+}
+
+// ContainsOrAdd implements LRU.
+func (t *threadsafe[K, V]) ContainsOrAdd(key K, val V) (existed, evicted bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.ContainsOrAdd(key, val)
+}
+
+// PeekOrAdd implements LRU.
+func (t *threadsafe[K, V]) PeekOrAdd(key K, val V) (prev V, existed, evicted bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.PeekOrAdd(key, val)
+}
+
 // Replace implements LRU.
 func (t *threadsafe[K, V]) Replace(key K, val V) (existed bool) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+	t.lock()
+	defer t.unlock()
 
 	return t.lru.Replace(key, val)
 }
 
+// Upsert implements LRU.
+func (t *threadsafe[K, V]) Upsert(key K, val V) (updated bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Upsert(key, val)
+}
+
 // Reset implements LRU.
 func (t *threadsafe[K, V]) Reset() {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+	t.lock()
+	defer t.unlock()
 
 	t.lru.Reset()
 }
 
 // Resize implements LRU.
-func (t *threadsafe[K, V]) Resize(capacity int) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+func (t *threadsafe[K, V]) Resize(capacity int, opts ...ResizeOption) []EntryInfo[K, V] {
+	t.lock()
+	defer t.unlock()
 
-	t.lru.Resize(capacity)
+	return t.lru.Resize(capacity, opts...)
 }
 
 // Set implements LRU.
 func (t *threadsafe[K, V]) Set(key K, val V) (ok bool) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+	t.lock()
+	defer t.unlock()
 
 	return t.lru.Set(key, val)
 }
+
+// Start a background refresh of an item that is about to expire. Must hold the write lock.
+func (t *threadsafe[K, V]) refresh(key K, setter func(K) (V, time.Duration, error)) {
+	idx, ok := t.lru.index(key)
+
+	if !ok || t.lru.expires[idx] == 0 || !t.lru.alive(idx) || t.lru.expires[idx]-t.lru.now() > t.lru.refreshAhead {
+		return
+	}
+
+	key = t.lru.canonical(key)
+
+	if _, ok = t.refreshing[key]; ok || !t.lru.limiter.allow(t.lru.now()) {
+		return
+	}
+
+	if t.refreshing == nil {
+		t.refreshing = make(map[K]struct{})
+	}
+
+	t.refreshing[key] = struct{}{}
+	t.refreshes.Add(1)
+
+	go func() {
+		defer t.refreshes.Done()
+
+		val, ttl, err := callSafely(setter, key)
+
+		t.lock()
+		defer t.unlock()
+
+		delete(t.refreshing, key)
+
+		// Don't resurrect an item that was removed meanwhile
+		if _, ok := t.lru.index(key); ok && err == nil {
+			t.lru.put(key, val, t.lru.loadedExpiry(val, ttl))
+		}
+	}()
+}
+
+// Call a setter in the background, where a panic is returned as an error.
+func callSafely[K comparable, V any](setter func(K) (V, time.Duration, error), key K) (val V, ttl time.Duration, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("lru: setter panicked: %v", p)
+		}
+	}()
+
+	return setter(key)
+}
@@ -0,0 +1,348 @@
+package lru
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSampledPromotion(t *testing.T) {
+	cache := NewThreadSafeWithOptions(2, WithSampledPromotion[int, int](1))
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Get(1)
+	cache.Set(3, 3)
+
+	if !cache.Has(1) || cache.Has(2) {
+		t.Fatal("expected every hit to be promoted with a sample rate of 1")
+	}
+}
+
+func TestSampledPromotionConcurrent(t *testing.T) {
+	cache := NewThreadSafeWithOptions(256, WithSampledPromotion[int, int](8))
+
+	for i := range 64 {
+		cache.Set(i, i)
+	}
+
+	var wg sync.WaitGroup
+
+	for g := range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range 1000 {
+				if v, ok := cache.Get(i % 64); !ok || v != i%64 {
+					t.Errorf("expected %d, got %d", i%64, v)
+					return
+				}
+
+				if i%100 == 0 {
+					cache.Replace(g*1000+i, i)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if s := cache.Stats(); s.Hits == 0 {
+		t.Fatalf("expected hits to be counted, got %+v", s)
+	}
+}
+
+func TestConcurrentGet(t *testing.T) {
+	cache := NewThreadSafe[int, int](64)
+
+	for i := range 64 {
+		cache.Set(i, i)
+	}
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range 1000 {
+				cache.Get(i % 64)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if n := len(cache.Keys()); n != 64 {
+		t.Fatalf("expected 64 keys in recency order, got %d", n)
+	}
+}
+
+func TestBufferedPromotion(t *testing.T) {
+	cache := NewThreadSafeWithOptions(3, WithBufferedPromotion[int, int](16))
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Set(3, 3)
+	cache.Get(1)
+	cache.Get(2)
+
+	// The insert applies the buffered promotions before evicting
+	cache.Set(4, 4)
+
+	if !cache.Has(1) || !cache.Has(2) || cache.Has(3) {
+		t.Fatalf("expected buffered promotions to be applied, got %v", cache.Keys())
+	}
+}
+
+func TestBufferedPromotionConcurrent(t *testing.T) {
+	cache := NewThreadSafeWithOptions(256, WithBufferedPromotion[int, int](8))
+
+	for i := range 64 {
+		cache.Set(i, i)
+	}
+
+	var wg sync.WaitGroup
+
+	for g := range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range 1000 {
+				if v, ok := cache.Get(i % 64); !ok || v != i%64 {
+					t.Errorf("expected %d, got %d", i%64, v)
+					return
+				}
+
+				if i%100 == 0 {
+					cache.Replace(1000+g*1000+i, i)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestThreadSafeIterateWrite(t *testing.T) {
+	cache := NewThreadSafe[int, int](8)
+
+	for i := range 8 {
+		cache.Set(i, i)
+	}
+
+	for k := range cache.IterateAsc() {
+		if k%2 == 0 {
+			cache.Remove(k)
+		} else {
+			cache.Set(k+100, k)
+		}
+	}
+
+	if cache.Len() != 8 || cache.Has(0) || !cache.Has(101) {
+		t.Fatalf("expected writes during iteration to apply, got %v", cache.Keys())
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	cache := NewThreadSafe[int, int](4)
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Get(1)
+
+	snap := cache.Snapshot()
+	cache.Remove(1)
+
+	if !slices.Equal(snap.Keys(), []int{2, 1}) {
+		t.Fatalf("expected snapshot to keep recency, got %v", snap.Keys())
+	}
+
+	snap.Set(3, 3)
+
+	if cache.Has(3) || cache.Len() != 1 {
+		t.Fatal("expected snapshot to be detached")
+	}
+}
+
+func TestRefreshAhead(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewThreadSafeWithOptions(8, WithClock[int, int](clock), WithRefreshAhead[int, int](time.Second))
+	calls := make(chan struct{}, 8)
+	value := 1
+	setter := func(int) (int, error) {
+		calls <- struct{}{}
+		return value, nil
+	}
+
+	cache.GetOrSetTTL(1, 10*time.Second, setter)
+	<-calls
+	value = 2
+	clock.Advance(9500 * time.Millisecond)
+
+	if v, _ := cache.GetOrSetTTL(1, 10*time.Second, setter); v != 1 {
+		t.Fatalf("expected the stale value during refresh, got %d", v)
+	}
+
+	<-calls
+
+	for range 100 {
+		if info, _ := cache.Entry(1); info.Value == 2 {
+			if info.TTL != 10*time.Second {
+				t.Fatalf("expected refreshed ttl, got %v", info.TTL)
+			}
+
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("expected the item to be refreshed in the background")
+}
+
+func TestEvictedOutsideLock(t *testing.T) {
+	var cache LRU[int, int]
+
+	cache = NewThreadSafe(2, func(k int, _ int) {
+		// Would deadlock if called while holding the lock
+		if cache.Has(k) {
+			t.Errorf("expected %d to be evicted before notification", k)
+		}
+	})
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Set(3, 3)
+	cache.RemoveAll()
+}
+
+func TestPool(t *testing.T) {
+	pool := &sync.Pool{New: func() any {
+		return new([64]byte)
+	}}
+
+	cache := NewThreadSafeWithOptions(1,
+		WithEvicted(func(key int, val *[64]byte) {
+			if val[0] != byte(key) {
+				t.Error("expected the value to be intact when notified")
+			}
+		}),
+		WithPool[int, *[64]byte](pool),
+	)
+
+	for i := range 10 {
+		cache.GetOrSet(i, func(key int) (*[64]byte, error) {
+			buf := pool.Get().(*[64]byte)
+			buf[0] = byte(key)
+			return buf, nil
+		})
+	}
+
+	if v, _ := cache.Get(9); v[0] != 9 {
+		t.Fatalf("expected the newest value to be intact, got %d", v[0])
+	}
+}
+
+func TestReentrantCallbacks(t *testing.T) {
+	var cache LRU[int, int]
+
+	cache = NewThreadSafeWithOptions(4,
+		// Insert a tombstone for the first evicted item
+		WithEvicted(func(k int, _ int) {
+			if k == 1 {
+				cache.Replace(-k, -1)
+			}
+		}),
+		WithRejected(func(k int, _ int) {
+			cache.Remove(k)
+		}),
+	)
+
+	for i := 1; i <= 5; i++ {
+		cache.Set(i, i)
+	}
+
+	if !cache.Has(-1) || cache.Has(2) || cache.Len() != 4 {
+		t.Fatalf("expected a tombstone of the evicted item, got %v", cache.Keys())
+	}
+
+	cache.Resize(0)
+	cache.Set(4, 4)
+}
+
+func TestClone(t *testing.T) {
+	var evicted int
+
+	cache := NewThreadSafe(2, func(int, int) {
+		evicted++
+	})
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Get(1)
+
+	clone := cache.Clone()
+
+	if !slices.Equal(clone.Keys(), []int{2, 1}) {
+		t.Fatalf("expected clone to keep recency, got %v", clone.Keys())
+	}
+
+	clone.Set(3, 3)
+
+	if evicted != 1 || cache.Len() != 2 || !cache.Has(2) {
+		t.Fatal("expected clone to be independent, but keep the evict callback")
+	}
+
+	dst := New[int, int](4)
+	dst.Set(0, 0)
+	cache.CopyTo(dst)
+
+	if !slices.Equal(dst.Keys(), []int{0, 2, 1}) {
+		t.Fatalf("expected items to be copied in recency order, got %v", dst.Keys())
+	}
+}
+
+func TestCloneResizing(t *testing.T) {
+	cache := NewThreadSafe[int, int](10)
+
+	for i := range 10 {
+		cache.Set(i, i)
+	}
+
+	cache.Resize(5, ResizeLazy())
+	clone := cache.Clone()
+
+	if err := clone.Validate(); err != nil {
+		t.Fatalf("expected a clone to be shrinking too, got %v", err)
+	}
+
+	if clone.Set(10, 10); clone.Len() != 5 || clone.Validate() != nil {
+		t.Fatalf("expected the clone to finish shrinking, got %d items", clone.Len())
+	}
+}
+
+func TestGetOrSetPanic(t *testing.T) {
+	cache := NewThreadSafe[int, int](8)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the setter panic to propagate")
+			}
+		}()
+
+		cache.GetOrSet(1, func(int) (int, error) {
+			panic("boom")
+		})
+	}()
+
+	if cache.Has(1) || !cache.Set(1, 1) {
+		t.Fatal("expected nothing to be cached, and the cache to stay usable")
+	}
+}
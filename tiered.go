@@ -0,0 +1,104 @@
+package lru
+
+import "slices"
+
+// Store is a secondary tier of a Tiered cache, e.g. a larger cache or an external store.
+type Store[K comparable, V any] interface {
+	Load(key K) (val V, ok bool)
+	Store(key K, val V)
+	Delete(key K) (existed bool)
+}
+
+// Tiered is a cache of two tiers, where items evicted from the first tier are moved to the
+// second, and items found in the second tier are moved back to the first. An item only lives in
+// one tier at a time. Not thread-safe.
+type Tiered[K comparable, V any] struct {
+	hot      LRU[K, V]
+	warm     Store[K, V]
+	removing bool
+}
+
+// Create a tiered cache, with a first tier of the provided capacity and options. A callback of
+// WithEvicted is notified of items evicted from the first tier, after they're moved to the second.
+func NewTiered[K comparable, V any](capacity int, warm Store[K, V], opts ...Option[K, V]) *Tiered[K, V] {
+	t := &Tiered[K, V]{warm: warm}
+	t.hot = NewWithOptions(capacity, append(slices.Clip(opts), withEvictHook(t.demote))...)
+
+	return t
+}
+
+// Use a cache as the second tier of a Tiered cache.
+func StoreOf[K comparable, V any](c LRU[K, V]) Store[K, V] {
+	return lruStore[K, V]{c}
+}
+
+// Number of items in the first tier.
+func (t *Tiered[K, V]) Len() int {
+	return t.hot.Len()
+}
+
+// Get an item from the first tier, or move it from the second tier to the first.
+func (t *Tiered[K, V]) Get(key K) (val V, ok bool) {
+	if val, ok = t.hot.Get(key); ok {
+		return
+	}
+
+	if val, ok = t.warm.Load(key); ok {
+		t.warm.Delete(key)
+		t.hot.Upsert(key, val)
+	}
+
+	return
+}
+
+// Get an item from any tier, or set it with the setter if it doesn't exist in either.
+func (t *Tiered[K, V]) GetOrSet(key K, setter func(K) (V, error)) (val V, err error) {
+	var ok bool
+
+	if val, ok = t.Get(key); ok {
+		return
+	}
+
+	if val, err = setter(key); err == nil {
+		t.hot.Upsert(key, val)
+	}
+
+	return
+}
+
+// Add or overwrite an item in the first tier, and delete it from the second.
+func (t *Tiered[K, V]) Set(key K, val V) {
+	t.hot.Upsert(key, val)
+	t.warm.Delete(key)
+}
+
+// Remove an item from both tiers, without moving it to the second tier.
+func (t *Tiered[K, V]) Remove(key K) (existed bool) {
+	t.removing = true
+	existed = t.hot.Remove(key)
+	t.removing = false
+
+	return t.warm.Delete(key) || existed
+}
+
+func (t *Tiered[K, V]) demote(key K, val V) {
+	if !t.removing {
+		t.warm.Store(key, val)
+	}
+}
+
+type lruStore[K comparable, V any] struct {
+	c LRU[K, V]
+}
+
+func (s lruStore[K, V]) Load(key K) (val V, ok bool) {
+	return s.c.Get(key)
+}
+
+func (s lruStore[K, V]) Store(key K, val V) {
+	s.c.Upsert(key, val)
+}
+
+func (s lruStore[K, V]) Delete(key K) bool {
+	return s.c.Remove(key)
+}
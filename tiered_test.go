@@ -0,0 +1,61 @@
+package lru
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleTiered() {
+	warm := New[string, int](8)
+	cache := NewTiered(2, StoreOf(warm))
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	fmt.Println(cache.Len(), warm.Len())
+
+	v, ok := cache.Get("a")
+	fmt.Println(v, ok, warm.Has("a"), warm.Has("b"))
+
+	// Output:
+	// 2 1
+	// 1 true false true
+}
+
+func TestTieredRemove(t *testing.T) {
+	warm := New[int, int](8)
+	cache := NewTiered(2, StoreOf(warm))
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Set(3, 3)
+
+	if !cache.Remove(3) || !cache.Remove(1) {
+		t.Fatal("expected items in both tiers to be removed")
+	}
+
+	if warm.Len() != 0 || cache.Len() != 1 {
+		t.Fatalf("expected removed items to not be moved to the second tier, got %v", warm.Keys())
+	}
+
+	if v, err := cache.GetOrSet(1, func(int) (int, error) { return 10, nil }); err != nil || v != 10 {
+		t.Fatalf("expected setter to be called on a miss in both tiers, got %d", v)
+	}
+}
+
+func TestTieredEvicted(t *testing.T) {
+	var evicted []int
+
+	warm := New[int, int](8)
+	cache := NewTiered(1, StoreOf(warm), WithEvicted(func(key, _ int) {
+		evicted = append(evicted, key)
+	}))
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+
+	if len(evicted) != 1 || !warm.Has(1) {
+		t.Fatalf("expected the evict callback to be notified of a moved item, got %v", evicted)
+	}
+}
@@ -0,0 +1,112 @@
+package lru
+
+import (
+	"fmt"
+	"hash/maphash"
+	"io"
+	"sync"
+)
+
+// Record the hits and misses of 1 in n keys, e.g. into a TraceWriter or TraceRing. Sampling keys
+// rather than lookups keeps the reuse pattern of each sampled key intact, so that a trace sampled
+// at 1 in n can be simulated at 1/n of the capacity. The function is called synchronously, and
+// must be safe for concurrent use when used by a thread-safe cache.
+func WithTrace[K comparable, V any](record func(Event[K]), n int) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.trace = tracer[K]{
+			record: record,
+			every:  uint64(max(n, 1)),
+			seed:   maphash.MakeSeed(),
+		}
+	}
+}
+
+type tracer[K comparable] struct {
+	record func(Event[K])
+	every  uint64
+	seed   maphash.Seed
+}
+
+func (c *lru[K, V]) traced(kind EventKind, key K) {
+	t := &c.trace
+
+	if t.record == nil || (t.every > 1 && hashKey(t.seed, key)%t.every != 0) {
+		return
+	}
+
+	t.record(Event[K]{
+		Kind: kind,
+		Key:  key,
+		Time: c.clock.Now(),
+	})
+}
+
+// TraceWriter writes recorded lookups as tab-separated lines of key, kind and Unix nanoseconds,
+// which lrusim.ReadLines can replay. Keys are formatted with fmt, and must not contain tabs or
+// newlines. Safe for concurrent use.
+type TraceWriter[K comparable] struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// Create a trace writer. Wrap w in a bufio.Writer to reduce writes, and flush it when done.
+func NewTraceWriter[K comparable](w io.Writer) *TraceWriter[K] {
+	return &TraceWriter[K]{w: w}
+}
+
+// Write a lookup. Use as the recorder of WithTrace.
+func (t *TraceWriter[K]) Record(e Event[K]) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.err == nil {
+		_, t.err = fmt.Fprintf(t.w, "%v\t%s\t%d\n", e.Key, e.Kind, e.Time.UnixNano())
+	}
+}
+
+// First write error, if any. No lookups are written after an error.
+func (t *TraceWriter[K]) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.err
+}
+
+// TraceRing keeps the most recently recorded lookups in memory. Safe for concurrent use.
+type TraceRing[K comparable] struct {
+	mu     sync.Mutex
+	events []Event[K]
+	next   int
+}
+
+// Create a ring of the n most recently recorded lookups.
+func NewTraceRing[K comparable](n int) *TraceRing[K] {
+	return &TraceRing[K]{events: make([]Event[K], 0, max(n, 1))}
+}
+
+// Keep a lookup, and forget the oldest one if full. Use as the recorder of WithTrace.
+func (t *TraceRing[K]) Record(e Event[K]) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.events) < cap(t.events) {
+		t.events = append(t.events, e)
+	} else {
+		t.events[t.next] = e
+	}
+
+	t.next = (t.next + 1) % cap(t.events)
+}
+
+// Kept lookups, from oldest to newest.
+func (t *TraceRing[K]) Events() []Event[K] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.events) < cap(t.events) {
+		return append([]Event[K](nil), t.events...)
+	}
+
+	return append(append(make([]Event[K], 0, len(t.events)), t.events[t.next:]...), t.events[:t.next]...)
+}
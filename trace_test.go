@@ -0,0 +1,74 @@
+package lru
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTraceWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	clock := &fakeClock{now: time.Unix(0, 42)}
+	w := NewTraceWriter[string](&buf)
+	cache := NewWithOptions(2, WithTrace[string, int](w.Record, 1), WithClock[string, int](clock))
+	cache.Set("a", 1)
+	cache.Get("a")
+	cache.Get("b")
+
+	if err := w.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := buf.String(), "a\thit\t42\nb\tmiss\t42\n"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestTraceSampling(t *testing.T) {
+	ring := NewTraceRing[int](1000)
+	cache := NewWithOptions(10, WithTrace[int, int](ring.Record, 4))
+
+	for range 2 {
+		for i := range 400 {
+			cache.Get(i)
+		}
+	}
+
+	events := ring.Events()
+	sampled := make(map[int]int)
+
+	for _, e := range events {
+		sampled[e.Key]++
+	}
+
+	// Sampled keys are recorded on every lookup.
+	for key, n := range sampled {
+		if n != 2 {
+			t.Fatalf("expected key %d to be recorded twice, got %d", key, n)
+		}
+	}
+
+	if len(sampled) < 50 || len(sampled) > 150 {
+		t.Fatalf("expected about 100 sampled keys, got %d", len(sampled))
+	}
+}
+
+func TestTraceRing(t *testing.T) {
+	ring := NewTraceRing[int](2)
+
+	for i := range 3 {
+		ring.Record(Event[int]{Kind: EventMiss, Key: i})
+	}
+
+	var keys []string
+
+	for _, e := range ring.Events() {
+		keys = append(keys, e.Kind.String()+string(rune('0'+e.Key)))
+	}
+
+	if got := strings.Join(keys, " "); got != "miss1 miss2" {
+		t.Fatalf("expected the 2 newest events, got %q", got)
+	}
+}
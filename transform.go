@@ -0,0 +1,19 @@
+package lru
+
+// Transform each key on the way into the cache, e.g. strings.ToLower for case-insensitive keys, or
+// unique.Make(key).Value() to intern them. Applies to every operation, including aliases, and
+// iterators return transformed keys. The transform must be idempotent.
+func WithKeyTransform[K comparable, V any](transform func(key K) K) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.transform = transform
+	}
+}
+
+// The canonical form of a key.
+func (c *lru[K, V]) canonical(key K) K {
+	if c.transform != nil {
+		return c.transform(key)
+	}
+
+	return key
+}
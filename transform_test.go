@@ -0,0 +1,36 @@
+package lru
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyTransform(t *testing.T) {
+	cache := NewThreadSafeWithOptions(4, WithKeyTransform[string, int](strings.ToLower))
+
+	cache.Set("Foo", 1)
+
+	if v, ok := cache.Get("FOO"); !ok || v != 1 {
+		t.Fatalf("expected a case-insensitive hit, got %d", v)
+	}
+
+	cache.Upsert("foo", 2)
+
+	if cache.Len() != 1 {
+		t.Fatalf("expected a single item, got %d", cache.Len())
+	}
+
+	for k, v := range cache.Iterate() {
+		if k != "foo" || v != 2 {
+			t.Fatalf("expected the transformed key, got %q", k)
+		}
+	}
+
+	if !cache.Alias("Bar", "FOO") || !cache.Has("bar") {
+		t.Fatal("expected the alias to be transformed")
+	}
+
+	if !cache.Remove("BAR") || cache.Len() != 0 {
+		t.Fatal("expected the item to be removed")
+	}
+}
@@ -0,0 +1,112 @@
+package lru
+
+import (
+	"iter"
+	"sync/atomic"
+	"time"
+)
+
+// Expire items ttl after they were written, unless written with a ttl of their own, e.g. for
+// configuration that must be reloaded periodically.
+func WithExpireAfterWrite[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.writeTTL = int64(max(ttl, 0))
+	}
+}
+
+// Expire items once idle for ttl, i.e. neither read nor written, e.g. for sessions. An item with a
+// write expiry expires at whichever comes first. Touch counts as a use, while Has, Entry and
+// iterators don't.
+func WithExpireAfterAccess[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.idle = int64(max(ttl, 0))
+	}
+}
+
+// Expiry of an item in Unix nanoseconds, i.e. the earliest of its write and idle expiry, or zero
+// if never.
+func (c *lru[K, V]) deadline(idx int) int64 {
+	exp := c.expires[idx]
+
+	if c.idle > 0 {
+		m := &c.meta[idx]
+
+		if idle := max(m.created, atomic.LoadInt64(&m.accessed)) + c.idle; exp == 0 || idle < exp {
+			exp = idle
+		}
+	}
+
+	return exp
+}
+
+// Same as Replace, but the item expires after ttl. A ttl of zero or less never expires, unless
+// WithExpireAfterWrite is used.
+func (c *lru[K, V]) SetWithTTL(key K, val V, ttl time.Duration) (existed bool) {
+	existed = c.put(key, val, c.expiry(ttl))
+	c.change(key)
+	return
+}
+
+// Change when an unexpired item expires, without affecting recency. A zero time never expires.
+func (c *lru[K, V]) ExpireAt(key K, t time.Time) (ok bool) {
+	idx, ok := c.index(key)
+
+	if !ok || !c.alive(idx) {
+		return false
+	}
+
+	c.expires[idx] = 0
+
+	if !t.IsZero() {
+		c.expires[idx] = t.UnixNano()
+	}
+
+	return
+}
+
+// Remaining lifetime of an unexpired item, or zero if it never expires. Doesn't affect recency.
+func (c *lru[K, V]) TTL(key K) (ttl time.Duration, ok bool) {
+	idx, ok := c.index(key)
+
+	if !ok || !c.alive(idx) {
+		return 0, false
+	}
+
+	if exp := c.deadline(idx); exp != 0 {
+		ttl = time.Duration(exp - c.now())
+	}
+
+	return
+}
+
+// Remove all expired items, and notify each evict. Returns the number of removed items.
+func (c *lru[K, V]) PurgeExpired() (removed int) {
+	// Walk backwards, as removal swaps the last item into the removed slot.
+	for i := len(c.keys) - 1; i >= 0; i-- {
+		if !c.alive(i) {
+			c.remove(i)
+			removed++
+		}
+	}
+
+	return
+}
+
+// Iterate all expired items that are yet to be removed, in no particular order.
+func (c *lru[K, V]) IterateExpired() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		gen := c.gen
+
+		for i := range c.keys {
+			if c.alive(i) {
+				continue
+			}
+
+			if !yield(c.keys[i], c.copy(c.vals[i])) {
+				return
+			}
+
+			c.unchanged(gen)
+		}
+	}
+}
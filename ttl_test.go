@@ -0,0 +1,126 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetWithTTL(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewWithOptions(8, WithClock[int, int](clock))
+
+	cache.SetWithTTL(1, 1, time.Minute)
+	cache.Set(2, 2)
+
+	if ttl, ok := cache.TTL(1); !ok || ttl != time.Minute {
+		t.Fatalf("expected a ttl of a minute, got %v", ttl)
+	}
+
+	if ttl, ok := cache.TTL(2); !ok || ttl != 0 {
+		t.Fatalf("expected no ttl, got %v", ttl)
+	}
+
+	clock.Advance(30 * time.Second)
+
+	if !cache.ExpireAt(1, clock.Now().Add(time.Hour)) {
+		t.Fatal("expected an unexpired item to be extended")
+	}
+
+	clock.Advance(time.Minute)
+
+	if ttl, ok := cache.TTL(1); !ok || ttl != time.Hour-time.Minute {
+		t.Fatalf("expected an extended ttl, got %v", ttl)
+	}
+
+	cache.ExpireAt(2, clock.Now().Add(time.Second))
+	clock.Advance(time.Second)
+
+	if _, ok := cache.TTL(2); ok || cache.ExpireAt(2, time.Time{}) {
+		t.Fatal("expected an expired item to be missing")
+	}
+}
+
+func TestPurgeExpired(t *testing.T) {
+	var evicted []int
+
+	clock := newFakeClock()
+	cache := NewWithOptions(8, WithClock[int, int](clock), WithEvicted(func(k int, _ int) {
+		evicted = append(evicted, k)
+	}))
+
+	cache.SetWithTTL(1, 1, time.Second)
+	cache.SetWithTTL(2, 2, time.Minute)
+	cache.Set(3, 3)
+	clock.Advance(time.Second)
+
+	var expired []int
+
+	for k := range cache.IterateExpired() {
+		expired = append(expired, k)
+	}
+
+	if len(expired) != 1 || expired[0] != 1 {
+		t.Fatalf("expected one expired item, got %v", expired)
+	}
+
+	if n := cache.PurgeExpired(); n != 1 || cache.Len() != 2 || len(evicted) != 1 {
+		t.Fatalf("expected the expired item to be purged, got %d", n)
+	}
+}
+
+func TestExpireAfterWrite(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewWithOptions(8, WithClock[int, int](clock), WithExpireAfterWrite[int, int](time.Minute))
+
+	cache.Set(1, 1)
+	cache.SetWithTTL(2, 2, time.Hour)
+
+	clock.Advance(30 * time.Second)
+	cache.Get(1)
+
+	if ttl, _ := cache.TTL(1); ttl != 30*time.Second {
+		t.Fatalf("expected reads to not extend the ttl, got %v", ttl)
+	}
+
+	cache.Upsert(1, 10)
+	clock.Advance(45 * time.Second)
+
+	if !cache.Has(1) || !cache.Has(2) {
+		t.Fatal("expected writes to restart the ttl")
+	}
+
+	clock.Advance(30 * time.Second)
+
+	if cache.Has(1) || !cache.Has(2) {
+		t.Fatal("expected only the default ttl to have expired")
+	}
+}
+
+func TestExpireAfterAccess(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewWithOptions(8, WithClock[int, int](clock), WithExpireAfterAccess[int, int](time.Minute))
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.SetWithTTL(3, 3, 90*time.Second)
+
+	for range 3 {
+		clock.Advance(40 * time.Second)
+		cache.Get(1)
+		cache.Get(3)
+	}
+
+	if !cache.Has(1) || cache.Has(2) {
+		t.Fatal("expected only the idle item to expire")
+	}
+
+	if cache.Has(3) {
+		t.Fatal("expected the write expiry to apply despite use")
+	}
+
+	clock.Advance(50 * time.Second)
+
+	if ttl, ok := cache.TTL(1); !ok || ttl != 10*time.Second {
+		t.Fatalf("expected 10s until idle expiry, got %v", ttl)
+	}
+}
@@ -0,0 +1,126 @@
+package lru
+
+import "fmt"
+
+// Check the internal invariants of the cache, and return an ErrCorrupt error describing the first
+// violation, if any. Takes time linear in the number of items.
+func (c *lru[K, V]) Validate() error {
+	n := len(c.keys)
+
+	if len(c.vals) != n || len(c.older) != n || len(c.newer) != n || len(c.expires) != n || len(c.pinned) != n || len(c.meta) != n {
+		return corrupt("storage of %d keys has mismatched lengths", n)
+	}
+
+	index := make(map[K]int, n)
+	pins := 0
+
+	for i, key := range c.keys {
+		if j, ok := index[key]; ok {
+			return corrupt("duplicate key %v at %d and %d", key, j, i)
+		}
+
+		if c.pinned[i] {
+			pins++
+		}
+
+		index[key] = i
+	}
+
+	if pins != c.pins {
+		return corrupt("%d pinned items counted as %d", pins, c.pins)
+	}
+
+	if err := c.validateOrder(); err != nil {
+		return err
+	}
+
+	for alias, primary := range c.aliases {
+		if _, ok := index[primary]; !ok {
+			return corrupt("alias %v of missing key %v", alias, primary)
+		}
+
+		if _, ok := index[alias]; ok {
+			return corrupt("alias %v is also a key", alias)
+		}
+	}
+
+	for tag, keys := range c.tags {
+		for key := range keys {
+			if _, ok := index[key]; !ok {
+				return corrupt("tag %q of missing key %v", tag, key)
+			}
+		}
+	}
+
+	for key, tags := range c.tagsOf {
+		for _, tag := range tags {
+			if _, ok := c.tags[tag][key]; !ok {
+				return corrupt("key %v lacks its tag %q", key, tag)
+			}
+		}
+	}
+
+	if c.resizing == 0 && n > max(c.limit(), 0) {
+		return corrupt("%d items exceed the capacity of %d", n, c.limit())
+	}
+
+	return nil
+}
+
+// Check that the recency order links every item exactly once, in both directions.
+func (c *lru[K, V]) validateOrder() error {
+	n := len(c.keys)
+
+	if n == 0 {
+		if c.oldest != -1 || c.newest != -1 {
+			return corrupt("empty cache has oldest %d and newest %d", c.oldest, c.newest)
+		}
+
+		return nil
+	}
+
+	count, small := 0, 0
+	split, prev := -1, -1
+
+	for idx := c.oldest; idx >= 0; idx = c.newer[idx] {
+		if count++; idx >= n || count > n {
+			return corrupt("recency order has a cycle or an index out of range")
+		}
+
+		if c.older[idx] != prev {
+			return corrupt("item %d links to older %d instead of %d", idx, c.older[idx], prev)
+		}
+
+		if c.meta[idx].small {
+			if small++; split < 0 {
+				split = idx
+			}
+		} else if split >= 0 {
+			return corrupt("item %d of the main queue is newer than the small queue", idx)
+		}
+
+		prev = idx
+	}
+
+	if count != n || prev != c.newest {
+		return corrupt("recency order links %d of %d items, ending at %d instead of %d", count, n, prev, c.newest)
+	}
+
+	if s := c.s3; s != nil && (s.small != small || s.split != split) {
+		return corrupt("small queue of %d items from %d counted as %d from %d", small, split, s.small, s.split)
+	}
+
+	return nil
+}
+
+func corrupt(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrCorrupt, fmt.Sprintf(format, args...))
+}
+
+// Validate implements LRU.
+func (t *threadsafe[K, V]) Validate() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.lru.Validate()
+}
@@ -0,0 +1,55 @@
+package lru
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, cache := range []LRU[int, int]{NewThreadSafe[int, int](32), NewS3FIFO[int, int](32)} {
+		for i := range 5000 {
+			key := rand.IntN(64)
+
+			switch rand.IntN(8) {
+			case 0:
+				cache.Remove(key)
+			case 1:
+				cache.Pin(key)
+			case 2:
+				cache.Unpin(key)
+			case 3:
+				cache.Alias(key+100, key)
+			case 4:
+				cache.SetTagged(key, i, "tag")
+			case 5:
+				cache.Resize(16 + rand.IntN(32))
+			default:
+				cache.Replace(key, i)
+			}
+
+			if err := cache.Validate(); err != nil {
+				t.Fatalf("expected a valid cache after %d operations, got %v", i, err)
+			}
+		}
+	}
+
+	cache := New[int, int](8).(*lru[int, int])
+
+	for i := range 4 {
+		cache.Set(i, i)
+	}
+
+	cache.newer[1] = 0
+
+	if err := cache.Validate(); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected a cycle to be detected, got %v", err)
+	}
+
+	cache.newer[1] = 2
+	cache.keys[3] = 0
+
+	if err := cache.Validate(); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected a duplicate key to be detected, got %v", err)
+	}
+}
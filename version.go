@@ -0,0 +1,113 @@
+package lru
+
+// Same as Get, but also returns the version of the item, for use with CompareAndSwap.
+func (c *lru[K, V]) GetVersioned(key K) (val V, version uint64, ok bool) {
+	idx, ok := c.lookup(key)
+
+	if ok {
+		c.promoteOn(idx, PromoteOnGet)
+		val = c.copy(c.vals[idx])
+		version = c.meta[idx].version
+	}
+
+	return
+}
+
+// Overwrite an unexpired item only if its version is unchanged, and mark it as most recently used.
+// A version of zero adds the item only if it doesn't exist. The item keeps its expiry, and the
+// overwritten value is released but not notified as evicted.
+func (c *lru[K, V]) CompareAndSwap(key K, version uint64, val V) (swapped bool) {
+	idx, found := c.index(key)
+
+	if found && c.alive(idx) {
+		if c.meta[idx].version != version {
+			return false
+		}
+
+		c.replaceValue(idx, c.copy(val))
+		c.promoteOn(idx, PromoteOnSet)
+		c.meta[idx].version = c.nextVersion()
+		c.weigh(idx)
+	} else if version != 0 {
+		return false
+	} else if found {
+		c.overwrite(idx, val, 0)
+	} else if !c.append(key, val, 0) {
+		return false
+	}
+
+	c.change(key)
+	return true
+}
+
+// Compute a new value from the current one, if any, or remove the item if fn returns delete. The
+// item is marked as most recently used and keeps its expiry, and a replaced value is released but
+// not notified as evicted. Returns the resulting value, and whether the item exists. fn must not call the
+// cache.
+func (c *lru[K, V]) Compute(key K, fn func(old V, exists bool) (val V, delete bool)) (val V, ok bool) {
+	var old V
+
+	idx, found := c.index(key)
+	exists := found && c.alive(idx)
+
+	if exists {
+		old = c.copy(c.vals[idx])
+	}
+
+	val, del := fn(old, exists)
+
+	if del {
+		if found {
+			c.remove(idx)
+		}
+
+		if exists {
+			c.change(key)
+		}
+
+		return val, false
+	}
+
+	if exists {
+		c.replaceValue(idx, c.copy(val))
+		c.promoteOn(idx, PromoteOnSet)
+		c.meta[idx].version = c.nextVersion()
+		c.weigh(idx)
+	} else if found {
+		c.overwrite(idx, val, 0)
+	} else if !c.append(key, val, 0) {
+		return val, false
+	}
+
+	c.change(key)
+	return val, true
+}
+
+func (c *lru[K, V]) nextVersion() uint64 {
+	c.version++
+	return c.version
+}
+
+// GetVersioned implements LRU.
+func (t *threadsafe[K, V]) GetVersioned(key K) (val V, version uint64, ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.GetVersioned(key)
+}
+
+// Compute implements LRU.
+func (t *threadsafe[K, V]) Compute(key K, fn func(old V, exists bool) (val V, delete bool)) (val V, ok bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.Compute(key, fn)
+}
+
+// CompareAndSwap implements LRU.
+func (t *threadsafe[K, V]) CompareAndSwap(key K, version uint64, val V) (swapped bool) {
+	t.lock()
+	defer t.unlock()
+
+	return t.lru.CompareAndSwap(key, version, val)
+}
@@ -0,0 +1,85 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCompareAndSwap(t *testing.T) {
+	cache := New[int, int](8)
+
+	if !cache.CompareAndSwap(1, 0, 1) || cache.CompareAndSwap(1, 0, 2) {
+		t.Fatal("expected version zero to only add a missing item")
+	}
+
+	_, version, _ := cache.GetVersioned(1)
+
+	if !cache.CompareAndSwap(1, version, 2) || cache.CompareAndSwap(1, version, 3) {
+		t.Fatal("expected a stale version to fail")
+	}
+
+	if v, _ := cache.Get(1); v != 2 {
+		t.Fatalf("expected the swapped value, got %d", v)
+	}
+}
+
+func TestCompareAndSwapConcurrent(t *testing.T) {
+	cache := NewThreadSafe[int, int](8)
+	cache.Set(1, 0)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				for {
+					v, version, _ := cache.GetVersioned(1)
+
+					if cache.CompareAndSwap(1, version, v+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if v, _ := cache.Get(1); v != 800 {
+		t.Fatalf("expected no lost updates, got %d", v)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	cache := NewThreadSafe[string, int](8)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				cache.Compute("n", func(old int, _ bool) (int, bool) {
+					return old + 1, false
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if v, _ := cache.Get("n"); v != 800 {
+		t.Fatalf("expected no lost updates, got %d", v)
+	}
+
+	if _, ok := cache.Compute("n", func(int, bool) (int, bool) { return 0, true }); ok || cache.Has("n") {
+		t.Fatal("expected the item to be deleted")
+	}
+}
@@ -0,0 +1,56 @@
+package lru
+
+import "iter"
+
+// Choose the item evicted due to capacity among the n least recently used items that aren't
+// pinned, e.g. to spare items that are checked out by a request. The candidates are yielded from
+// least to most recently used, and if the returned key isn't one of them, the least recently used
+// is evicted. The selector must not call the cache, and takes precedence over WithCostFn.
+func WithVictimSelector[K comparable, V any](n int, selector func(candidates iter.Seq2[K, V]) K) Option[K, V] {
+	return func(c *lru[K, V]) {
+		c.selector = selector
+		c.candidates = max(n, 1)
+	}
+}
+
+// Index of the item chosen by the victim selector, or -1 if all items are pinned.
+func (c *lru[K, V]) selectVictim() int {
+	first := c.oldestUnpinned()
+
+	if first < 0 {
+		return -1
+	}
+
+	key := c.selector(func(yield func(K, V) bool) {
+		for idx := range c.victimCandidates(first) {
+			if !yield(c.keys[idx], c.vals[idx]) {
+				return
+			}
+		}
+	})
+
+	for idx := range c.victimCandidates(first) {
+		if c.keys[idx] == key {
+			return idx
+		}
+	}
+
+	return first
+}
+
+// Iterate the candidates of the victim selector, starting at the least recently used unpinned item.
+func (c *lru[K, V]) victimCandidates(first int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for idx, n := first, 0; idx >= 0 && n < c.candidates; idx = c.newer[idx] {
+			if c.pinned[idx] {
+				continue
+			}
+
+			if !yield(idx) {
+				return
+			}
+
+			n++
+		}
+	}
+}
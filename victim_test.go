@@ -0,0 +1,47 @@
+package lru
+
+import (
+	"iter"
+	"testing"
+)
+
+func TestVictimSelector(t *testing.T) {
+	checkedOut := map[int]bool{1: true, 2: true}
+
+	var seen []int
+
+	cache := NewWithOptions(4, WithVictimSelector(3, func(candidates iter.Seq2[int, int]) int {
+		seen = seen[:0]
+
+		for k := range candidates {
+			seen = append(seen, k)
+		}
+
+		for _, k := range seen {
+			if !checkedOut[k] {
+				return k
+			}
+		}
+
+		return -1
+	}))
+
+	for i := range 4 {
+		cache.Set(i+1, i+1)
+	}
+
+	cache.Pin(1)
+	cache.Set(5, 5)
+
+	if len(seen) != 3 || seen[0] != 2 || cache.Has(3) || !cache.Has(2) {
+		t.Fatalf("expected the oldest item that isn't checked out to be evicted, got candidates %v", seen)
+	}
+
+	// Falls back to the least recently used candidate
+	checkedOut[4], checkedOut[5] = true, true
+	cache.Set(6, 6)
+
+	if cache.Has(2) || !cache.Has(1) {
+		t.Fatal("expected the least recently used candidate to be evicted")
+	}
+}